@@ -29,11 +29,21 @@ func Connect() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	// Only migrate User table in auth service
-	err = DB.AutoMigrate(&models.User{})
+	// Users who signed up before email verification existed count as verified
+	grandfather := DB.Migrator().HasTable(&models.User{}) && !DB.Migrator().HasColumn(&models.User{}, "EmailVerifiedAt")
+
+	// Auth service owns users and token tables
+	err = DB.AutoMigrate(&models.User{}, &models.RefreshToken{}, &models.RevokedToken{}, &models.AccountToken{})
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
-	log.Println("Auth Service: Database connected and User table migrated!")
+	if grandfather {
+		err = DB.Model(&models.User{}).Unscoped().Where("email_verified_at IS NULL").UpdateColumn("email_verified_at", gorm.Expr("created_at")).Error
+		if err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+	}
+
+	log.Println("Auth Service: Database connected and auth tables migrated!")
 }
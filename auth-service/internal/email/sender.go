@@ -0,0 +1,79 @@
+package email
+
+import (
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Sender sends plain-text email
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPSender sends email over SMTP
+type SMTPSender struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPSender configures a sender from SMTP_HOST (default smtp.gmail.com),
+// SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM
+// (default the username)
+func NewSMTPSender() *SMTPSender {
+	username := os.Getenv("SMTP_USERNAME")
+	sender := &SMTPSender{
+		Host:     getEnv("SMTP_HOST", "smtp.gmail.com"),
+		Port:     getEnv("SMTP_PORT", "587"),
+		Username: username,
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     getEnv("SMTP_FROM", username),
+	}
+	if !sender.configured() {
+		log.Println("Warning: SMTP_USERNAME or SMTP_PASSWORD not set, account emails are not sent")
+	}
+	return sender
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (s *SMTPSender) configured() bool {
+	return s.Username != "" && s.Password != ""
+}
+
+// Send delivers one email. Without SMTP credentials it only logs the
+// subject and recipient, as account emails carry secret links.
+func (s *SMTPSender) Send(to, subject, body string) error {
+	if !s.configured() {
+		log.Printf("📧 SMTP not configured - would send: %s to %s", subject, to)
+		return nil
+	}
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", to)
+	}
+
+	auth := smtp.PlainAuth("", s.Username, s.Password, s.Host)
+
+	msg := []string{
+		fmt.Sprintf("To: %s", to),
+		fmt.Sprintf("From: %s", s.From),
+		fmt.Sprintf("Subject: %s", mime.QEncoding.Encode("utf-8", subject)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=\"UTF-8\"",
+		"",
+		body,
+	}
+
+	return smtp.SendMail(s.Host+":"+s.Port, auth, s.From, []string{to}, []byte(strings.Join(msg, "\r\n")))
+}
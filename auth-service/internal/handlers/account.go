@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"task-management-auth-service/internal/database"
+	"task-management-auth-service/internal/email"
+	"task-management-auth-service/internal/models"
+	"task-management-auth-service/pkg/utils"
+	"time"
+)
+
+const (
+	passwordResetTTL     = time.Hour
+	emailVerificationTTL = 48 * time.Hour
+	// accountEmailInterval is the least time between two account emails of
+	// the same kind to one user, so repeated requests can't flood an inbox
+	accountEmailInterval = time.Minute
+)
+
+// Mailer sends account emails; main sets it
+var Mailer email.Sender
+
+var (
+	errInvalidAccountToken = errors.New("invalid or expired token")
+	errAccountEmailTooSoon = errors.New("an email was sent moments ago")
+)
+
+const passwordResetEmail = `Hi %s,
+
+Someone asked to reset the password of your Task Management account. To choose
+a new password, open this link within an hour:
+
+%s/reset-password?token=%s
+
+If you didn't ask for this, ignore this email; your password stays the same.
+`
+
+const emailVerificationEmail = `Hi %s,
+
+Please confirm this is your email address by opening this link within two days:
+
+%s/verify-email?token=%s
+
+If you didn't create a Task Management account, ignore this email.
+`
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// appURL is the frontend the links in account emails point to, from APP_URL
+func appURL() string {
+	if url := strings.TrimRight(os.Getenv("APP_URL"), "/"); url != "" {
+		return url
+	}
+	return "http://localhost:3000"
+}
+
+// createAccountToken issues a token for the purpose and returns it. The
+// user's earlier unused tokens for the purpose are dropped, so only the
+// newest link works.
+func createAccountToken(user models.User, purpose string, ttl time.Duration) (string, error) {
+	token, err := utils.GenerateAccountToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		var recent int64
+		err := tx.Model(&models.AccountToken{}).
+			Where("user_id = ? AND purpose = ? AND created_at > ?", user.ID, purpose, now.Add(-accountEmailInterval)).
+			Count(&recent).Error
+		if err != nil {
+			return err
+		}
+		if recent > 0 {
+			return errAccountEmailTooSoon
+		}
+
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", user.ID, purpose).Delete(&models.AccountToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.AccountToken{
+			UserID:    user.ID,
+			Purpose:   purpose,
+			TokenHash: utils.HashToken(token),
+			Email:     user.Email,
+			ExpiresAt: now.Add(ttl),
+		}).Error
+	})
+	return token, err
+}
+
+// useAccountToken marks a token used inside tx and returns it, or fails
+// with errInvalidAccountToken if it is unknown, used or expired
+func useAccountToken(tx *gorm.DB, token, purpose string) (models.AccountToken, error) {
+	var stored models.AccountToken
+	if err := tx.Where("token_hash = ? AND purpose = ?", utils.HashToken(token), purpose).First(&stored).Error; err != nil {
+		return stored, errInvalidAccountToken
+	}
+	if stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return stored, errInvalidAccountToken
+	}
+
+	// Guard against two concurrent uses of the same token
+	result := tx.Model(&models.AccountToken{}).Where("id = ? AND used_at IS NULL", stored.ID).Update("used_at", time.Now())
+	if result.Error != nil {
+		return stored, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return stored, errInvalidAccountToken
+	}
+	return stored, nil
+}
+
+// sendPasswordResetEmail emails the user a link to choose a new password
+func sendPasswordResetEmail(user models.User) error {
+	token, err := createAccountToken(user, models.TokenPasswordReset, passwordResetTTL)
+	if err != nil {
+		return err
+	}
+	return Mailer.Send(user.Email, "Reset your password", fmt.Sprintf(passwordResetEmail, user.Name, appURL(), token))
+}
+
+// sendVerificationEmail emails the user a link to verify their address
+func sendVerificationEmail(user models.User) error {
+	token, err := createAccountToken(user, models.TokenEmailVerification, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+	return Mailer.Send(user.Email, "Verify your email address", fmt.Sprintf(emailVerificationEmail, user.Name, appURL(), token))
+}
+
+// ForgotPassword emails a password reset link. The response is the same
+// whether or not the account exists, and the email is sent in the
+// background so response times don't tell either.
+func ForgotPassword(context *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.Where("email = ?", req.Email).First(&user).Error; err == nil && user.DeactivatedAt == nil {
+		go func() {
+			if err := sendPasswordResetEmail(user); err != nil && !errors.Is(err, errAccountEmailTooSoon) {
+				log.Printf("❌ Failed to send password reset email to user %d: %v", user.ID, err)
+			}
+		}()
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "If an account exists for that email, a password reset link has been sent",
+	})
+}
+
+// ResetPassword sets a new password with a token from a reset email and
+// ends every session of the user
+func ResetPassword(context *gin.Context) {
+	var req ResetPasswordRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(req.Password)
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to hash password"})
+		return
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		stored, err := useAccountToken(tx, req.Token, models.TokenPasswordReset)
+		if err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.First(&user, stored.UserID).Error; err != nil {
+			return errInvalidAccountToken
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{"password": hashedPassword}
+		// Opening the link proved the user owns the address it was sent to
+		if user.EmailVerifiedAt == nil && user.Email == stored.Email {
+			updates["email_verified_at"] = now
+		}
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		// Whoever knew the old password is logged out
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", now).Error
+	})
+	if errors.Is(err, errInvalidAccountToken) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset password"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Password reset successfully",
+	})
+}
+
+// VerifyEmail marks the user's email address verified with a token from a
+// verification email
+func VerifyEmail(context *gin.Context) {
+	var req VerifyEmailRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		stored, err := useAccountToken(tx, req.Token, models.TokenEmailVerification)
+		if err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.First(&user, stored.UserID).Error; err != nil {
+			return errInvalidAccountToken
+		}
+		// The address changed since the email was sent
+		if user.Email != stored.Email {
+			return errInvalidAccountToken
+		}
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+		return tx.Model(&user).Update("email_verified_at", time.Now()).Error
+	})
+	if errors.Is(err, errInvalidAccountToken) {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify email"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Email verified successfully",
+	})
+}
+
+// ResendVerificationEmail sends the current user a new verification link
+func ResendVerificationEmail(context *gin.Context) {
+	userID := context.GetUint("user_id")
+
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.EmailVerifiedAt != nil {
+		context.JSON(http.StatusConflict, gin.H{"error": "Email is already verified"})
+		return
+	}
+
+	err := sendVerificationEmail(user)
+	if errors.Is(err, errAccountEmailTooSoon) {
+		context.JSON(http.StatusTooManyRequests, gin.H{"error": "A verification email was just sent, try again in a minute"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to send verification email to user %d: %v", user.ID, err)
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send verification email"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Verification email sent",
+	})
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"task-management-auth-service/internal/database"
+	"task-management-auth-service/internal/models"
+	"time"
+)
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+func adminUserResponse(user models.User) gin.H {
+	return gin.H{
+		"id":             user.ID,
+		"name":           user.Name,
+		"email":          user.Email,
+		"role":           user.Role,
+		"active":         user.DeactivatedAt == nil,
+		"deactivated_at": user.DeactivatedAt,
+		"created_at":     user.CreatedAt,
+	}
+}
+
+// ListUsers lists every user for admins, optionally filtered by ?role= and ?active=
+func ListUsers(context *gin.Context) {
+	query := database.DB.Order("id")
+
+	if role := context.Query("role"); role != "" {
+		query = query.Where("role = ?", role)
+	}
+	switch context.Query("active") {
+	case "true":
+		query = query.Where("deactivated_at IS NULL")
+	case "false":
+		query = query.Where("deactivated_at IS NOT NULL")
+	}
+
+	var users []models.User
+	if err := query.Find(&users).Error; err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch users"})
+		return
+	}
+
+	userList := make([]gin.H, 0, len(users))
+	for _, user := range users {
+		userList = append(userList, adminUserResponse(user))
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"users": userList,
+	})
+}
+
+// DeactivateUser blocks a user from logging in and revokes their refresh tokens
+func DeactivateUser(context *gin.Context) {
+	var user models.User
+	if err := database.DB.First(&user, context.Param("id")).Error; err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.ID == context.GetUint("user_id") {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "You cannot deactivate your own account"})
+		return
+	}
+
+	now := time.Now()
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("deactivated_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", now).Error
+	})
+	if err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to deactivate user"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "User deactivated successfully",
+		"user":    adminUserResponse(user),
+	})
+}
+
+// ReactivateUser lets a deactivated user log in again
+func ReactivateUser(context *gin.Context) {
+	var user models.User
+	if err := database.DB.First(&user, context.Param("id")).Error; err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := database.DB.Model(&user).Update("deactivated_at", nil).Error; err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reactivate user"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "User reactivated successfully",
+		"user":    adminUserResponse(user),
+	})
+}
+
+// UpdateUserRole changes a user's role; it takes effect on their next token
+func UpdateUserRole(context *gin.Context) {
+	var req UpdateRoleRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Role != models.RoleUser && req.Role != models.RoleAdmin {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role. Use: user, admin"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, context.Param("id")).Error; err != nil {
+		context.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if err := database.DB.Model(&user).Update("role", req.Role).Error; err != nil {
+		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "User role updated successfully",
+		"user":    adminUserResponse(user),
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"github.com/gin-gonic/gin"
+	"log"
 	"net/http"
 	"task-management-auth-service/internal/database"
 	"task-management-auth-service/internal/models"
@@ -45,7 +46,7 @@ func Register(context *gin.Context) {
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: hashedPassword,
-		Role:     "user",
+		Role:     models.RoleUser,
 	}
 
 	if err := database.DB.Create(&user).Error; err != nil {
@@ -53,21 +54,31 @@ func Register(context *gin.Context) {
 		return
 	}
 
-	// generate JWT token
-	token, err := utils.GenerateJWT(user.ID, user.Email)
+	// Ask the user to confirm their address, without holding up the response
+	go func() {
+		if err := sendVerificationEmail(user); err != nil {
+			log.Printf("❌ Failed to send verification email to user %d: %v", user.ID, err)
+		}
+	}()
+
+	// generate access and refresh tokens
+	tokens, err := issueTokens(user)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
 	context.JSON(http.StatusCreated, gin.H{
-		"message": "User created successfully",
-		"token":   token,
+		"message":       "User created successfully",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
 		"user": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"role":           user.Role,
+			"email_verified": user.EmailVerifiedAt != nil,
 		},
 	})
 }
@@ -92,21 +103,29 @@ func Login(context *gin.Context) {
 		return
 	}
 
-	// Provide token
-	token, err := utils.GenerateJWT(user.ID, user.Email)
+	if user.DeactivatedAt != nil {
+		context.JSON(http.StatusForbidden, gin.H{"error": "Account has been deactivated"})
+		return
+	}
+
+	// Provide tokens
+	tokens, err := issueTokens(user)
 	if err != nil {
 		context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
 	// Return success
-	context.JSON(http.StatusOK, gin.H{"token": token,
-		"message": "Successfully logged in",
+	context.JSON(http.StatusOK, gin.H{"token": tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+		"message":       "Successfully logged in",
 		"user": gin.H{
-			"id":    user.ID,
-			"name":  user.Name,
-			"email": user.Email,
-			"role":  user.Role,
+			"id":             user.ID,
+			"name":           user.Name,
+			"email":          user.Email,
+			"role":           user.Role,
+			"email_verified": user.EmailVerifiedAt != nil,
 		},
 	})
 }
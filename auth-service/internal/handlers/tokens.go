@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"strings"
+	"task-management-auth-service/internal/database"
+	"task-management-auth-service/internal/models"
+	"task-management-auth-service/pkg/utils"
+	"time"
+)
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type tokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int
+}
+
+// issueTokens creates an access token and a persisted refresh token for the user
+func issueTokens(user models.User) (tokenPair, error) {
+	return issueTokensTx(database.DB, user, func(*models.RefreshToken) error { return nil })
+}
+
+// issueTokensTx issues a token pair inside tx, calling onCreate with the new
+// refresh token row so callers can link it to the one it replaces
+func issueTokensTx(tx *gorm.DB, user models.User, onCreate func(*models.RefreshToken) error) (tokenPair, error) {
+	accessToken, err := utils.GenerateJWT(user.ID, user.Email, user.Role)
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return tokenPair{}, err
+	}
+
+	record := models.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(refreshToken),
+		ExpiresAt: time.Now().Add(utils.RefreshTokenTTL),
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return tokenPair{}, err
+	}
+	if err := onCreate(&record); err != nil {
+		return tokenPair{}, err
+	}
+
+	return tokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair, rotating the refresh token
+func Refresh(context *gin.Context) {
+	var req RefreshRequest
+	if err := context.ShouldBindJSON(&req); err != nil {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var stored models.RefreshToken
+	if err := database.DB.Where("token_hash = ?", utils.HashToken(req.RefreshToken)).First(&stored).Error; err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	// A revoked token being presented again means it leaked; end every session for the user
+	if stored.RevokedAt != nil {
+		database.DB.Model(&models.RefreshToken{}).
+			Where("user_id = ? AND revoked_at IS NULL", stored.UserID).
+			Update("revoked_at", time.Now())
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has been revoked"})
+		return
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "Refresh token has expired"})
+		return
+	}
+
+	var user models.User
+	if err := database.DB.First(&user, stored.UserID).Error; err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+	if user.DeactivatedAt != nil {
+		context.JSON(http.StatusForbidden, gin.H{"error": "Account has been deactivated"})
+		return
+	}
+
+	var tokens tokenPair
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		tokens, err = issueTokensTx(tx, user, func(replacement *models.RefreshToken) error {
+			// Guard against two concurrent refreshes of the same token
+			result := tx.Model(&models.RefreshToken{}).
+				Where("id = ? AND revoked_at IS NULL", stored.ID).
+				Updates(map[string]interface{}{"revoked_at": time.Now(), "replaced_by": replacement.ID})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+			return nil
+		})
+		return err
+	})
+	if err != nil {
+		context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message":       "Token refreshed successfully",
+		"token":         tokens.AccessToken,
+		"refresh_token": tokens.RefreshToken,
+		"expires_in":    tokens.ExpiresIn,
+	})
+}
+
+// Logout revokes the given refresh token and denylists the bearer access token
+func Logout(context *gin.Context) {
+	var req LogoutRequest
+	if err := context.ShouldBindJSON(&req); err != nil && context.Request.ContentLength > 0 {
+		context.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var claims map[string]interface{}
+	if authHeader := context.GetHeader("Authorization"); strings.HasPrefix(authHeader, "Bearer ") {
+		parsed, err := utils.ValidateJWT(strings.TrimPrefix(authHeader, "Bearer "))
+		if err != nil {
+			context.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+		claims = parsed
+	}
+
+	if claims == nil && req.RefreshToken == "" {
+		context.JSON(http.StatusBadRequest, gin.H{"error": "An access token or refresh_token is required"})
+		return
+	}
+
+	if req.RefreshToken != "" {
+		database.DB.Model(&models.RefreshToken{}).
+			Where("token_hash = ? AND revoked_at IS NULL", utils.HashToken(req.RefreshToken)).
+			Update("revoked_at", time.Now())
+	}
+
+	if jti, ok := claims["jti"].(string); ok {
+		expiresAt := time.Now().Add(utils.AccessTokenTTL)
+		if exp, ok := claims["exp"].(float64); ok {
+			expiresAt = time.Unix(int64(exp), 0)
+		}
+
+		revoked := models.RevokedToken{JTI: jti, ExpiresAt: expiresAt}
+		if err := database.DB.Where(models.RevokedToken{JTI: jti}).FirstOrCreate(&revoked).Error; err != nil {
+			context.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
+			return
+		}
+
+		// Expired tokens are rejected anyway, so their denylist entries can go
+		database.DB.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{})
+	}
+
+	context.JSON(http.StatusOK, gin.H{
+		"message": "Successfully logged out",
+	})
+}
+
+// JWKS publishes the public keys tokens are signed with
+func JWKS(context *gin.Context) {
+	context.Header("Cache-Control", "public, max-age=300")
+	context.JSON(http.StatusOK, utils.JWKS())
+}
@@ -4,7 +4,11 @@ import (
 	"fmt"
 	"log"
 	"task-management-auth-service/internal/database"
+	"task-management-auth-service/internal/email"
 	"task-management-auth-service/internal/handlers"
+	"task-management-auth-service/internal/middleware"
+	"task-management-auth-service/internal/models"
+	"task-management-auth-service/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +17,14 @@ func main() {
 	// Connect to database
 	database.Connect()
 
+	// Load the keys tokens are signed with
+	if err := utils.LoadSigningKeys(); err != nil {
+		log.Fatal("Failed to load JWT signing keys:", err)
+	}
+
+	// Password reset and verification links are emailed
+	handlers.Mailer = email.NewSMTPSender()
+
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -40,6 +52,23 @@ func main() {
 	{
 		auth.POST("/register", handlers.Register)
 		auth.POST("/login", handlers.Login)
+		auth.POST("/refresh", handlers.Refresh)
+		auth.POST("/logout", handlers.Logout)
+		auth.POST("/password/forgot", handlers.ForgotPassword)
+		auth.POST("/password/reset", handlers.ResetPassword)
+		auth.POST("/verify-email", handlers.VerifyEmail)
+		auth.POST("/verify-email/resend", middleware.RequireAuth(), handlers.ResendVerificationEmail)
+		auth.GET("/.well-known/jwks.json", handlers.JWKS)
+	}
+
+	// Admin routes (admin role only)
+	admin := r.Group("/admin")
+	admin.Use(middleware.RequireAuth(), middleware.RequireRole(models.RoleAdmin))
+	{
+		admin.GET("/users", handlers.ListUsers)
+		admin.PATCH("/users/:id/deactivate", handlers.DeactivateUser)
+		admin.PATCH("/users/:id/reactivate", handlers.ReactivateUser)
+		admin.PATCH("/users/:id/role", handlers.UpdateUserRole)
 	}
 
 	log.Println("🔐 Auth Service starting on port 8082")
@@ -47,6 +76,17 @@ func main() {
 	log.Println("   GET  /health")
 	log.Println("   POST /auth/register")
 	log.Println("   POST /auth/login")
+	log.Println("   POST /auth/refresh")
+	log.Println("   POST /auth/logout")
+	log.Println("   POST /auth/password/forgot")
+	log.Println("   POST /auth/password/reset")
+	log.Println("   POST /auth/verify-email")
+	log.Println("   POST /auth/verify-email/resend")
+	log.Println("   GET  /auth/.well-known/jwks.json")
+	log.Println("   GET  /admin/users")
+	log.Println("   PATCH /admin/users/:id/deactivate")
+	log.Println("   PATCH /admin/users/:id/reactivate")
+	log.Println("   PATCH /admin/users/:id/role")
 
 	if err := r.Run(":8082"); err != nil {
 		log.Fatal("Failed to start auth service:", err)
@@ -0,0 +1,193 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v5"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// signingKey is a private key that auth-service signs tokens with,
+// identified in token headers and the JWKS by its kid
+type signingKey struct {
+	KID     string
+	Private crypto.Signer
+	Method  jwt.SigningMethod
+}
+
+var (
+	signingKeys map[string]*signingKey
+	activeKey   *signingKey
+)
+
+// LoadSigningKeys loads the signing keys. Keys are read from the PEM files in
+// JWT_KEYS_DIR (kid = file name without extension), or from a single PEM in
+// JWT_PRIVATE_KEY. To rotate, add a new key file and point JWT_ACTIVE_KID at
+// it; keep the old file until tokens signed with it have expired. Without
+// any configuration an ephemeral RSA key is generated.
+func LoadSigningKeys() error {
+	keys := map[string]*signingKey{}
+	var kids []string
+
+	if dir := os.Getenv("JWT_KEYS_DIR"); dir != "" {
+		files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+			key, err := parseSigningKey(kid, data)
+			if err != nil {
+				return fmt.Errorf("%s: %w", file, err)
+			}
+			keys[kid] = key
+			kids = append(kids, kid)
+		}
+	} else if pemData := os.Getenv("JWT_PRIVATE_KEY"); pemData != "" {
+		key, err := parseSigningKey("", []byte(pemData))
+		if err != nil {
+			return fmt.Errorf("JWT_PRIVATE_KEY: %w", err)
+		}
+		keys[key.KID] = key
+		kids = append(kids, key.KID)
+	} else {
+		log.Println("Warning: no JWT signing keys configured, generating an ephemeral RSA key")
+		private, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return err
+		}
+		key := &signingKey{KID: thumbprint(&private.PublicKey), Private: private, Method: jwt.SigningMethodRS256}
+		keys[key.KID] = key
+		kids = append(kids, key.KID)
+	}
+
+	if len(keys) == 0 {
+		return errors.New("no JWT signing keys found")
+	}
+
+	// Without an explicit active kid, the last key by name signs
+	sort.Strings(kids)
+	activeKID := os.Getenv("JWT_ACTIVE_KID")
+	if activeKID == "" {
+		activeKID = kids[len(kids)-1]
+	}
+	active, ok := keys[activeKID]
+	if !ok {
+		return fmt.Errorf("active signing key %q not found", activeKID)
+	}
+
+	signingKeys = keys
+	activeKey = active
+	log.Printf("Loaded %d JWT signing key(s), active kid: %s (%s)", len(keys), active.KID, active.Method.Alg())
+	return nil
+}
+
+func parseSigningKey(kid string, data []byte) (*signingKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &signingKey{KID: kid}
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Private = private
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		key.Private = private
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, errors.New("only RSA and Ed25519 keys are supported")
+	}
+
+	if key.KID == "" {
+		key.KID = thumbprint(key.Private.Public())
+	}
+	return key, nil
+}
+
+// thumbprint derives a stable kid from a public key
+func thumbprint(public crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "default"
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// verificationKey returns the public key for a token's kid and algorithm
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := signingKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, errors.New("unexpected signing method")
+	}
+	return key.Private.Public(), nil
+}
+
+// JWKS returns the public signing keys as a JSON Web Key Set
+func JWKS() map[string]interface{} {
+	kids := make([]string, 0, len(signingKeys))
+	for kid := range signingKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+
+	keys := make([]map[string]string, 0, len(kids))
+	for _, kid := range kids {
+		key := signingKeys[kid]
+		jwk := map[string]string{
+			"kid": key.KID,
+			"use": "sig",
+			"alg": key.Method.Alg(),
+		}
+		switch public := key.Private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk["kty"] = "RSA"
+			jwk["n"] = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk["e"] = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk["kty"] = "OKP"
+			jwk["crv"] = "Ed25519"
+			jwk["x"] = base64.RawURLEncoding.EncodeToString(public)
+		}
+		keys = append(keys, jwk)
+	}
+
+	return map[string]interface{}{"keys": keys}
+}
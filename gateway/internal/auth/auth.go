@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task-management-pkg/identity"
+	"task-management-pkg/jwks"
+)
+
+var identitySecret = []byte(os.Getenv("GATEWAY_IDENTITY_SECRET"))
+
+// publicPaths are reachable without a token. Entries ending in "/" match as prefixes.
+var publicPaths = []string{
+	"/health",
+	"/gateway/health",
+	"/auth/register",
+	"/auth/login",
+	"/auth/refresh",
+	"/auth/logout",
+	"/auth/password/",
+	"/auth/verify-email",
+	"/auth/.well-known/",
+}
+
+func isPublic(path string) bool {
+	for _, public := range publicPaths {
+		if path == public || (strings.HasSuffix(public, "/") && strings.HasPrefix(path, public)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Authenticate validates the bearer token once at the edge and forwards the
+// verified identity to downstream services as signed X-User-* headers.
+// Before refusing a request, it asks throttled (if not nil) whether the
+// client has failed too often; throttled answers those requests itself.
+func Authenticate(throttled func(c *gin.Context) bool) gin.HandlerFunc {
+	if len(identitySecret) == 0 {
+		log.Println("Warning: GATEWAY_IDENTITY_SECRET not set, identity headers are unsigned")
+	}
+
+	unauthorized := func(c *gin.Context, message string) {
+		if throttled != nil && throttled(c) {
+			return
+		}
+		c.JSON(http.StatusUnauthorized, gin.H{"error": message})
+		c.Abort()
+	}
+
+	return func(c *gin.Context) {
+		for _, header := range identity.Headers {
+			c.Request.Header.Del(header)
+		}
+
+		if c.Request.Method == http.MethodOptions || isPublic(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			authHeader = streamToken(c.Request)
+		}
+		if authHeader == "" {
+			unauthorized(c, "Authorization header required")
+			return
+		}
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			unauthorized(c, "Invalid authorization format")
+			return
+		}
+
+		claims, err := jwks.Validate(parts[1])
+		if err != nil {
+			unauthorized(c, "Invalid or expired token")
+			return
+		}
+
+		userIDFloat, ok := claims["user_id"].(float64)
+		if !ok {
+			unauthorized(c, "Invalid user ID in token")
+			return
+		}
+
+		userID := strconv.FormatUint(uint64(userIDFloat), 10)
+		email, _ := claims["email"].(string)
+		role, _ := claims["role"].(string)
+		tokenID, _ := claims["jti"].(string)
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+		c.Request.Header.Set(identity.HeaderUserID, userID)
+		c.Request.Header.Set(identity.HeaderUserEmail, email)
+		c.Request.Header.Set(identity.HeaderUserRole, role)
+		c.Request.Header.Set(identity.HeaderTokenID, tokenID)
+		if len(identitySecret) > 0 {
+			c.Request.Header.Set(identity.HeaderTimestamp, timestamp)
+			c.Request.Header.Set(identity.HeaderSignature, identity.Sign(identitySecret, userID, email, role, tokenID, timestamp))
+		}
+
+		c.Set("user_id", uint(userIDFloat))
+		c.Next()
+	}
+}
+
+// streamToken takes the token of an event stream request from its
+// access_token query parameter, as browsers' EventSource can't set headers.
+// The parameter is removed so it isn't forwarded or logged.
+func streamToken(req *http.Request) string {
+	if !strings.Contains(req.Header.Get("Accept"), "text/event-stream") {
+		return ""
+	}
+
+	query := req.URL.Query()
+	token := query.Get("access_token")
+	if token == "" {
+		return ""
+	}
+	query.Del("access_token")
+	req.URL.RawQuery = query.Encode()
+	return "Bearer " + token
+}
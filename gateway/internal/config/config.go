@@ -0,0 +1,297 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the gateway's declarative route table
+type Config struct {
+	Upstreams  map[string]Upstream `json:"upstreams" yaml:"upstreams"`
+	Routes     []Route             `json:"routes" yaml:"routes"`
+	RateLimits []RateLimit         `json:"rate_limits,omitempty" yaml:"rate_limits,omitempty"`
+}
+
+// Upstream is a named backend service with one or more instances
+type Upstream struct {
+	// URL is a single instance, or a comma-separated list of instances
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+	// Targets lists instances explicitly and takes precedence over URL
+	Targets []string `json:"targets,omitempty" yaml:"targets,omitempty"`
+	// Balancer is "round_robin" (default) or "least_connections"
+	Balancer    string      `json:"balancer,omitempty" yaml:"balancer,omitempty"`
+	HealthCheck HealthCheck `json:"health_check" yaml:"health_check"`
+	// Timeout bounds how long an instance may take to send response headers,
+	// defaults to 30s. Response bodies may stream for longer.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Retries is how many other instances a failed GET or HEAD is retried
+	// on, defaults to 1
+	Retries        *int           `json:"retries,omitempty" yaml:"retries,omitempty"`
+	CircuitBreaker CircuitBreaker `json:"circuit_breaker" yaml:"circuit_breaker"`
+}
+
+// CircuitBreaker fast-fails requests to an upstream that keeps failing
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failed requests, across
+	// all instances, that opens the circuit, defaults to 5
+	FailureThreshold int `json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+	// OpenDuration is how long the circuit stays open before a trial
+	// request is let through, defaults to 30s
+	OpenDuration string `json:"open_duration,omitempty" yaml:"open_duration,omitempty"`
+}
+
+// HealthCheck controls passive ejection and background re-admission of instances
+type HealthCheck struct {
+	// Path probed on ejected instances, defaults to /health
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Interval between probes of ejected instances, defaults to 10s
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// FailureThreshold is the number of consecutive failed requests that
+	// ejects an instance, defaults to 3
+	FailureThreshold int `json:"failure_threshold,omitempty" yaml:"failure_threshold,omitempty"`
+}
+
+// Balancer names
+const (
+	RoundRobin       = "round_robin"
+	LeastConnections = "least_connections"
+)
+
+// Instances returns the upstream's instance URLs
+func (u Upstream) Instances() []string {
+	if len(u.Targets) > 0 {
+		return u.Targets
+	}
+
+	var instances []string
+	for _, target := range strings.Split(u.URL, ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			instances = append(instances, target)
+		}
+	}
+	return instances
+}
+
+// RequestTimeout returns the configured request timeout or its default
+func (u Upstream) RequestTimeout() time.Duration {
+	return parseDuration(u.Timeout, 30*time.Second)
+}
+
+// RetryCount returns the configured retry count or its default
+func (u Upstream) RetryCount() int {
+	if u.Retries != nil && *u.Retries >= 0 {
+		return *u.Retries
+	}
+	return 1
+}
+
+// ProbeInterval returns the configured probe interval or its default
+func (h HealthCheck) ProbeInterval() time.Duration {
+	return parseDuration(h.Interval, 10*time.Second)
+}
+
+// ProbePath returns the configured probe path or its default
+func (h HealthCheck) ProbePath() string {
+	if h.Path != "" {
+		return h.Path
+	}
+	return "/health"
+}
+
+// Threshold returns the configured failure threshold or its default
+func (h HealthCheck) Threshold() int {
+	if h.FailureThreshold > 0 {
+		return h.FailureThreshold
+	}
+	return 3
+}
+
+// Threshold returns the configured failure threshold or its default
+func (b CircuitBreaker) Threshold() int {
+	if b.FailureThreshold > 0 {
+		return b.FailureThreshold
+	}
+	return 5
+}
+
+// OpenFor returns the configured open duration or its default
+func (b CircuitBreaker) OpenFor() time.Duration {
+	return parseDuration(b.OpenDuration, 30*time.Second)
+}
+
+func parseDuration(value string, defaultValue time.Duration) time.Duration {
+	if duration, err := time.ParseDuration(value); err == nil && duration > 0 {
+		return duration
+	}
+	return defaultValue
+}
+
+// Route maps a path prefix (and optionally a set of methods) to an upstream.
+// When Rewrite is set, the matched prefix is replaced by it before forwarding.
+type Route struct {
+	Prefix   string   `json:"prefix" yaml:"prefix"`
+	Methods  []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	Upstream string   `json:"upstream" yaml:"upstream"`
+	Rewrite  string   `json:"rewrite,omitempty" yaml:"rewrite,omitempty"`
+}
+
+// RateLimit is a token-bucket policy for requests under a path prefix (and
+// optionally only some methods). Each client gets a bucket of Requests tokens
+// that refills at Requests per Period. The longest matching prefix applies.
+type RateLimit struct {
+	Prefix   string   `json:"prefix" yaml:"prefix"`
+	Methods  []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	Requests int      `json:"requests" yaml:"requests"`
+	Period   string   `json:"period" yaml:"period"`
+	// Key is "user" (default: the authenticated user, else the client IP)
+	// or "ip" (always the client IP)
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
+// Rate limit keys
+const (
+	KeyByUser = "user"
+	KeyByIP   = "ip"
+)
+
+// Window returns the refill period, defaulting to one minute
+func (r RateLimit) Window() time.Duration {
+	return parseDuration(r.Period, time.Minute)
+}
+
+// envPattern matches ${VAR} and ${VAR:-default} references in config files
+var envPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// Default reproduces the built-in routing, with upstream URLs taken from the
+// MONOLITH_URL, AUTH_SERVICE_URL, PROJECT_SERVICE_URL, TASK_SERVICE_URL and
+// NOTIFICATION_SERVICE_URL env vars. Each may list several comma-separated
+// instances.
+func Default() *Config {
+	return &Config{
+		Upstreams: map[string]Upstream{
+			"monolith":             {URL: getEnv("MONOLITH_URL", "http://localhost:8080")},
+			"auth-service":         {URL: getEnv("AUTH_SERVICE_URL", "http://localhost:8082")},
+			"project-service":      {URL: getEnv("PROJECT_SERVICE_URL", "http://localhost:8083")},
+			"task-service":         {URL: getEnv("TASK_SERVICE_URL", "http://localhost:8084")},
+			"notification-service": {URL: getEnv("NOTIFICATION_SERVICE_URL", "http://localhost:8085")},
+		},
+		Routes: []Route{
+			{Prefix: "/auth/", Upstream: "auth-service"},
+			{Prefix: "/admin/users", Upstream: "auth-service"},
+			{Prefix: "/projects", Upstream: "project-service"},
+			{Prefix: "/admin/projects", Upstream: "project-service"},
+			{Prefix: "/tasks", Upstream: "task-service"},
+			{Prefix: "/notifications", Upstream: "task-service"},
+			{Prefix: "/admin/notifications", Upstream: "notification-service"},
+			{Prefix: "/", Upstream: "monolith"},
+		},
+		RateLimits: []RateLimit{
+			{Prefix: "/auth/login", Requests: 5, Period: "1m", Key: KeyByIP},
+			{Prefix: "/auth/register", Requests: 3, Period: "1m", Key: KeyByIP},
+			{Prefix: "/auth/password/", Requests: 3, Period: "1m", Key: KeyByIP},
+			{Prefix: "/tasks", Requests: 120, Period: "1m", Key: KeyByUser},
+			{Prefix: "/", Requests: 300, Period: "1m", Key: KeyByUser},
+		},
+	}
+}
+
+// Load reads a YAML or JSON route table, expanding ${VAR:-default} references
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data = envPattern.ReplaceAllFunc(data, func(match []byte) []byte {
+		parts := envPattern.FindSubmatch(match)
+		return []byte(getEnv(string(parts[1]), string(parts[2])))
+	})
+
+	var cfg Config
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &cfg)
+	default:
+		err = yaml.Unmarshal(data, &cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate checks every route points at a defined upstream and every rate
+// limit is well-formed
+func (c *Config) Validate() error {
+	if len(c.Routes) == 0 {
+		return fmt.Errorf("no routes defined")
+	}
+	for name, upstream := range c.Upstreams {
+		if len(upstream.Instances()) == 0 {
+			return fmt.Errorf("upstream %q has no url or targets", name)
+		}
+		switch upstream.Balancer {
+		case "", RoundRobin, LeastConnections:
+		default:
+			return fmt.Errorf("upstream %q has unknown balancer %q", name, upstream.Balancer)
+		}
+		durations := map[string]string{
+			"health_check interval":         upstream.HealthCheck.Interval,
+			"timeout":                       upstream.Timeout,
+			"circuit_breaker open_duration": upstream.CircuitBreaker.OpenDuration,
+		}
+		for field, value := range durations {
+			if value == "" {
+				continue
+			}
+			if _, err := time.ParseDuration(value); err != nil {
+				return fmt.Errorf("upstream %q: invalid %s: %w", name, field, err)
+			}
+		}
+	}
+	for _, route := range c.Routes {
+		if !strings.HasPrefix(route.Prefix, "/") {
+			return fmt.Errorf("route prefix %q must start with /", route.Prefix)
+		}
+		if _, ok := c.Upstreams[route.Upstream]; !ok {
+			return fmt.Errorf("route %q uses unknown upstream %q", route.Prefix, route.Upstream)
+		}
+	}
+	for _, limit := range c.RateLimits {
+		if !strings.HasPrefix(limit.Prefix, "/") {
+			return fmt.Errorf("rate limit prefix %q must start with /", limit.Prefix)
+		}
+		if limit.Requests <= 0 {
+			return fmt.Errorf("rate limit %q needs a positive requests count", limit.Prefix)
+		}
+		if limit.Period != "" {
+			if _, err := time.ParseDuration(limit.Period); err != nil {
+				return fmt.Errorf("rate limit %q: invalid period: %w", limit.Prefix, err)
+			}
+		}
+		switch limit.Key {
+		case "", KeyByUser, KeyByIP:
+		default:
+			return fmt.Errorf("rate limit %q has unknown key %q", limit.Prefix, limit.Key)
+		}
+	}
+	return nil
+}
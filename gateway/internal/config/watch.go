@@ -0,0 +1,38 @@
+package config
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Watch polls the route file and calls onChange with the new config whenever
+// it changes. Invalid edits are logged and the previous config stays active.
+func Watch(path string, interval time.Duration, onChange func(*Config)) {
+	lastMod, lastSize := fileVersion(path)
+
+	for range time.Tick(interval) {
+		modTime, size := fileVersion(path)
+		if modTime.Equal(lastMod) && size == lastSize {
+			continue
+		}
+		lastMod, lastSize = modTime, size
+
+		cfg, err := Load(path)
+		if err != nil {
+			log.Printf("Route config reload failed, keeping previous routes: %v", err)
+			continue
+		}
+
+		log.Printf("Route config reloaded from %s (%d routes)", path, len(cfg.Routes))
+		onChange(cfg)
+	}
+}
+
+func fileVersion(path string) (time.Time, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0
+	}
+	return info.ModTime(), info.Size()
+}
@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// breaker is a circuit breaker for one upstream. It opens after threshold
+// consecutive failures, rejects requests while open, then lets a single trial
+// request through: success closes it, failure opens it again.
+type breaker struct {
+	name      string
+	threshold int
+	openFor   time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // zero while closed
+	trial    bool      // a half-open trial request is in flight
+}
+
+func newBreaker(name string, threshold int, openFor time.Duration) *breaker {
+	return &breaker{name: name, threshold: threshold, openFor: openFor}
+}
+
+// allow reports whether a request may proceed and, if not, how long until
+// the next trial request will be let through
+func (b *breaker) allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true, 0
+	}
+	if remaining := b.openFor - time.Since(b.openedAt); remaining > 0 {
+		return false, remaining
+	}
+	if b.trial {
+		return false, time.Second
+	}
+	b.trial = true
+	return true, 0
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.openedAt.IsZero() {
+		log.Printf("Circuit for %s closed", b.name)
+	}
+	b.failures = 0
+	b.openedAt = time.Time{}
+	b.trial = false
+}
+
+func (b *breaker) failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	halfOpen := b.trial
+	b.trial = false
+	if halfOpen || (b.openedAt.IsZero() && b.failures >= b.threshold) {
+		b.openedAt = time.Now()
+		log.Printf("Circuit for %s opened after %d consecutive failures", b.name, b.failures)
+	}
+}
+
+// release ends a trial request that finished without a verdict, e.g. because
+// the client went away
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+func (b *breaker) state() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.openedAt.IsZero():
+		return "closed"
+	case time.Since(b.openedAt) < b.openFor:
+		return "open"
+	default:
+		return "half_open"
+	}
+}
@@ -0,0 +1,300 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"task-management-gateway/internal/config"
+)
+
+// instance is one replica of an upstream
+type instance struct {
+	target *url.URL
+	proxy  *httputil.ReverseProxy
+
+	active   atomic.Int64 // requests in flight
+	failures atomic.Int32 // consecutive failed requests
+	ejected  atomic.Bool
+}
+
+// pool balances requests across an upstream's instances. Instances that fail
+// failureThreshold requests in a row are ejected until a probe of their
+// health path succeeds.
+type pool struct {
+	name             string
+	config           config.Upstream
+	instances        []*instance
+	leastConnections bool
+	next             atomic.Uint64
+	transport        *http.Transport
+	retries          int
+	breaker          *breaker
+
+	healthPath       string
+	probeInterval    time.Duration
+	failureThreshold int32
+	stop             chan struct{}
+}
+
+var probeClient = &http.Client{Timeout: 2 * time.Second}
+
+func newPool(name string, upstreamConfig config.Upstream) (*pool, error) {
+	p := &pool{
+		name:             name,
+		config:           upstreamConfig,
+		leastConnections: upstreamConfig.Balancer == config.LeastConnections,
+		healthPath:       upstreamConfig.HealthCheck.ProbePath(),
+		probeInterval:    upstreamConfig.HealthCheck.ProbeInterval(),
+		failureThreshold: int32(upstreamConfig.HealthCheck.Threshold()),
+		stop:             make(chan struct{}),
+		retries:          upstreamConfig.RetryCount(),
+		breaker: newBreaker(name, upstreamConfig.CircuitBreaker.Threshold(),
+			upstreamConfig.CircuitBreaker.OpenFor()),
+	}
+
+	// Bound connecting and waiting for headers, not the whole response, so
+	// long-lived streams keep working
+	p.transport = http.DefaultTransport.(*http.Transport).Clone()
+	p.transport.DialContext = (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	p.transport.ResponseHeaderTimeout = upstreamConfig.RequestTimeout()
+
+	for _, rawURL := range upstreamConfig.Instances() {
+		target, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("upstream %q: %w", name, err)
+		}
+		if target.Scheme == "" || target.Host == "" {
+			return nil, fmt.Errorf("upstream %q: invalid instance url %q", name, rawURL)
+		}
+		p.instances = append(p.instances, p.newInstance(target))
+	}
+
+	return p, nil
+}
+
+func (p *pool) newInstance(target *url.URL) *instance {
+	inst := &instance{target: target}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.Transport = p.transport
+
+	proxy.Director = func(req *http.Request) {
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+		req.Host = target.Host
+
+		log.Printf("Gateway → %s (%s): %s %s", p.name, target.Host, req.Method, req.URL.Path)
+	}
+
+	// Gateway-level failures count against the instance; application errors don't
+	proxy.ModifyResponse = func(resp *http.Response) error {
+		switch resp.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			p.recordFailure(inst)
+		default:
+			p.recordSuccess(inst)
+		}
+		return nil
+	}
+
+	// Errors are handed back to ServeHTTP, which retries or answers the client
+	proxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
+		if req.Context().Err() == nil {
+			log.Printf("%s (%s) proxy error: %v", p.name, target.Host, err)
+			p.recordFailure(inst)
+		}
+		if result, ok := req.Context().Value(attemptKey{}).(*attempt); ok {
+			result.err = err
+		}
+	}
+
+	inst.proxy = proxy
+	return inst
+}
+
+// pick chooses an instance for the next request, skipping those already
+// tried. When every remaining instance is ejected it still picks one, so a
+// recovering upstream isn't starved. It returns nil once all were tried.
+func (p *pool) pick(tried map[*instance]bool) *instance {
+	var healthy, untried []*instance
+	for _, inst := range p.instances {
+		if tried[inst] {
+			continue
+		}
+		untried = append(untried, inst)
+		if !inst.ejected.Load() {
+			healthy = append(healthy, inst)
+		}
+	}
+
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = untried
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if p.leastConnections {
+		best := candidates[0]
+		for _, inst := range candidates[1:] {
+			if inst.active.Load() < best.active.Load() {
+				best = inst
+			}
+		}
+		return best
+	}
+
+	return candidates[(p.next.Add(1)-1)%uint64(len(candidates))]
+}
+
+// serve forwards one attempt to the instance, counting it as in flight.
+// A response that breaks off mid-body, such as an event stream whose
+// upstream restarts, makes the proxy abort the handler; the client's
+// connection is simply closed then, so it can reconnect.
+func (inst *instance) serve(rw http.ResponseWriter, req *http.Request) {
+	inst.active.Add(1)
+	defer inst.active.Add(-1)
+	defer func() {
+		if err := recover(); err != nil && err != http.ErrAbortHandler {
+			panic(err)
+		}
+	}()
+
+	inst.proxy.ServeHTTP(rw, req)
+}
+
+// attempt carries the outcome of one forwarding attempt out of the proxy
+type attempt struct {
+	err error
+}
+
+type attemptKey struct{}
+
+// ServeHTTP forwards the request to one instance. GET and HEAD requests that
+// fail before a response arrives are retried on other instances; while the
+// circuit is open requests are rejected without reaching the upstream.
+func (p *pool) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	retries := 0
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		retries = p.retries
+	}
+
+	tried := map[*instance]bool{}
+	var lastErr error
+	for try := 0; try <= retries; try++ {
+		if ok, wait := p.breaker.allow(); !ok {
+			p.unavailable(rw, wait)
+			return
+		}
+
+		inst := p.pick(tried)
+		if inst == nil {
+			p.breaker.release()
+			break
+		}
+		tried[inst] = true
+
+		result := &attempt{}
+		inst.serve(rw, req.WithContext(context.WithValue(req.Context(), attemptKey{}, result)))
+
+		if result.err == nil {
+			return
+		}
+		if req.Context().Err() != nil {
+			// The client went away; there is nobody to answer
+			p.breaker.release()
+			return
+		}
+		lastErr = result.err
+		if try < retries {
+			log.Printf("Retrying %s %s on another %s instance", req.Method, req.URL.Path, p.name)
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(lastErr, &netErr) && netErr.Timeout() {
+		writeError(rw, http.StatusGatewayTimeout, fmt.Sprintf("Gateway: %s timed out", p.name))
+		return
+	}
+	writeError(rw, http.StatusBadGateway, fmt.Sprintf("Gateway: %s unavailable", p.name))
+}
+
+// unavailable fast-fails a request while the circuit is open
+func (p *pool) unavailable(rw http.ResponseWriter, wait time.Duration) {
+	rw.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	writeError(rw, http.StatusServiceUnavailable, fmt.Sprintf("Gateway: %s is temporarily unavailable", p.name))
+}
+
+func writeError(rw http.ResponseWriter, status int, message string) {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.WriteHeader(status)
+	rw.Write(body)
+}
+
+func (p *pool) balancer() string {
+	if p.leastConnections {
+		return config.LeastConnections
+	}
+	return config.RoundRobin
+}
+
+func (p *pool) recordFailure(inst *instance) {
+	p.breaker.failure()
+	if inst.failures.Add(1) >= p.failureThreshold && inst.ejected.CompareAndSwap(false, true) {
+		log.Printf("Ejected %s instance %s after %d consecutive failures", p.name, inst.target.Host, p.failureThreshold)
+	}
+}
+
+func (p *pool) recordSuccess(inst *instance) {
+	p.breaker.success()
+	inst.failures.Store(0)
+}
+
+// probe checks an instance's health path
+func (p *pool) probe(inst *instance) bool {
+	resp, err := probeClient.Get(inst.target.String() + p.healthPath)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// watch probes ejected instances in the background and re-admits those that
+// answer healthy, until the pool is replaced by a config reload
+func (p *pool) watch() {
+	ticker := time.NewTicker(p.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			for _, inst := range p.instances {
+				if inst.ejected.Load() && p.probe(inst) {
+					inst.failures.Store(0)
+					inst.ejected.Store(false)
+					log.Printf("Re-admitted %s instance %s", p.name, inst.target.Host)
+				}
+			}
+		}
+	}
+}
+
+func (p *pool) close() {
+	close(p.stop)
+	p.transport.CloseIdleConnections()
+}
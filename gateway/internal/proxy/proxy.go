@@ -1,246 +1,189 @@
 package proxy
 
 import (
-	"log"
 	"net/http"
-	"net/http/httputil"
-	"net/url"
-	"os"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
+	"task-management-gateway/internal/config"
 )
 
-var (
-	MONOLITH_BASE_URL   = getEnv("MONOLITH_URL", "http://localhost:8080")
-	AUTH_SERVICE_URL    = getEnv("AUTH_SERVICE_URL", "http://localhost:8082")
-	PROJECT_SERVICE_URL = getEnv("PROJECT_SERVICE_URL", "http://localhost:8083")
-	TASK_SERVICE_URL    = getEnv("TASK_SERVICE_URL", "http://localhost:8084")
-)
-
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
+// routeTable is a compiled route config: routes sorted longest prefix first
+// and one balanced instance pool per upstream
+type routeTable struct {
+	routes    []config.Route
+	upstreams map[string]*pool
 }
 
-func SmartProxy() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		path := c.Request.URL.Path
-
-		// Route /auth/* to Auth Service
-		if strings.HasPrefix(path, "/auth/") {
-			ForwardToAuthService(c)
-			return
-		}
+var (
+	current atomic.Pointer[routeTable]
+	// configureMu serializes reloads, which reuse the current table's pools
+	configureMu sync.Mutex
+)
 
-		// Route /projects/* to Project Service
-		if strings.HasPrefix(path, "/projects") {
-			ForwardToProjectService(c)
-			return
+// Configure compiles a route config and swaps it in for subsequent requests.
+// Upstreams whose config is unchanged keep their pool, and with it their
+// circuit breaker, ejected instances and in-flight counts; only new or
+// changed upstreams get a fresh one.
+func Configure(cfg *config.Config) error {
+	configureMu.Lock()
+	defer configureMu.Unlock()
+
+	table := &routeTable{
+		routes:    append([]config.Route(nil), cfg.Routes...),
+		upstreams: make(map[string]*pool, len(cfg.Upstreams)),
+	}
+
+	previous := current.Load()
+	var fresh []*pool
+	for name, upstreamConfig := range cfg.Upstreams {
+		if previous != nil {
+			if p, ok := previous.upstreams[name]; ok && reflect.DeepEqual(p.config, upstreamConfig) {
+				table.upstreams[name] = p
+				continue
+			}
 		}
 
-		// Route /tasks/* to Task Service
-		if strings.HasPrefix(path, "/tasks") {
-			ForwardToTaskService(c)
-			return
+		p, err := newPool(name, upstreamConfig)
+		if err != nil {
+			return err
 		}
-
-		// Route everything else to Monolith
-		ForwardToMonolith(c)
-	}
-}
-
-// ForwardToAuthService forwards auth requests to the auth service
-func ForwardToAuthService(c *gin.Context) {
-	target, err := url.Parse(AUTH_SERVICE_URL)
-	if err != nil {
-		log.Printf("Failed to parse auth service URL: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gateway configuration error"})
-		return
+		table.upstreams[name] = p
+		fresh = append(fresh, p)
 	}
 
-	proxy := httputil.NewSingleHostReverseProxy(target)
-
-	proxy.Director = func(req *http.Request) {
-		req.URL.Scheme = target.Scheme
-		req.URL.Host = target.Host
-		req.Host = target.Host
+	// Longest prefix wins; routes restricted to methods win over catch-alls of the same prefix
+	sort.SliceStable(table.routes, func(i, j int) bool {
+		if len(table.routes[i].Prefix) != len(table.routes[j].Prefix) {
+			return len(table.routes[i].Prefix) > len(table.routes[j].Prefix)
+		}
+		return len(table.routes[i].Methods) > len(table.routes[j].Methods)
+	})
 
-		log.Printf("Gateway → Auth Service: %s %s", req.Method, req.URL.Path)
+	for _, p := range fresh {
+		go p.watch()
 	}
 
-	proxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
-		log.Printf("Auth Service proxy error: %v", err)
-		rw.WriteHeader(http.StatusBadGateway)
-		rw.Write([]byte(`{"error": "Gateway: Auth service unavailable"}`))
+	// Stop probing the instances of the pools being replaced
+	current.Store(table)
+	if previous != nil {
+		for name, p := range previous.upstreams {
+			if table.upstreams[name] != p {
+				p.close()
+			}
+		}
 	}
-
-	proxy.ServeHTTP(c.Writer, c.Request)
+	return nil
 }
 
-// ForwardToTaskService forwards task requests to the task service
-func ForwardToTaskService(c *gin.Context) {
-	target, err := url.Parse(TASK_SERVICE_URL)
-	if err != nil {
-		log.Printf("Failed to parse task service URL: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gateway configuration error"})
-		return
-	}
-
-	proxy := httputil.NewSingleHostReverseProxy(target)
-
-	proxy.Director = func(req *http.Request) {
-		req.URL.Scheme = target.Scheme
-		req.URL.Host = target.Host
-		req.Host = target.Host
-
-		log.Printf("Gateway → Task Service: %s %s", req.Method, req.URL.Path)
-	}
-
-	proxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
-		log.Printf("Task Service proxy error: %v", err)
-		rw.WriteHeader(http.StatusBadGateway)
-		rw.Write([]byte(`{"error": "Gateway: Task service unavailable"}`))
+// match finds the route for a request
+func (t *routeTable) match(method, path string) (config.Route, bool) {
+	for _, route := range t.routes {
+		if !strings.HasPrefix(path, route.Prefix) {
+			continue
+		}
+		if len(route.Methods) > 0 && !containsMethod(route.Methods, method) {
+			continue
+		}
+		return route, true
 	}
-
-	proxy.ServeHTTP(c.Writer, c.Request)
+	return config.Route{}, false
 }
 
-// ForwardToProjectService forwards project requests to the project service
-func ForwardToProjectService(c *gin.Context) {
-	target, err := url.Parse(PROJECT_SERVICE_URL)
-	if err != nil {
-		log.Printf("Failed to parse project service URL: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gateway configuration error"})
-		return
-	}
-
-	proxy := httputil.NewSingleHostReverseProxy(target)
-
-	proxy.Director = func(req *http.Request) {
-		req.URL.Scheme = target.Scheme
-		req.URL.Host = target.Host
-		req.Host = target.Host
-
-		log.Printf("Gateway → Project Service: %s %s", req.Method, req.URL.Path)
-	}
-
-	proxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
-		log.Printf("Project Service proxy error: %v", err)
-		rw.WriteHeader(http.StatusBadGateway)
-		rw.Write([]byte(`{"error": "Gateway: Project service unavailable"}`))
+func containsMethod(methods []string, method string) bool {
+	for _, m := range methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
 	}
-
-	proxy.ServeHTTP(c.Writer, c.Request)
+	return false
 }
 
-// ForwardToMonolith forwards non-auth requests to the monolith
-func ForwardToMonolith(c *gin.Context) {
-	target, err := url.Parse(MONOLITH_BASE_URL)
-	if err != nil {
-		log.Printf("Failed to parse monolith URL: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Gateway configuration error"})
-		return
-	}
-
-	proxy := httputil.NewSingleHostReverseProxy(target)
+// SmartProxy forwards each request to the upstream of its matching route
+func SmartProxy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		table := current.Load()
+		if table == nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Gateway configuration error"})
+			return
+		}
 
-	proxy.Director = func(req *http.Request) {
-		req.URL.Scheme = target.Scheme
-		req.URL.Host = target.Host
-		req.Host = target.Host
+		route, ok := table.match(c.Request.Method, c.Request.URL.Path)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "No route for this path"})
+			return
+		}
 
-		log.Printf("Gateway → Monolith: %s %s", req.Method, req.URL.Path)
-	}
+		if route.Rewrite != "" {
+			c.Request.URL.Path = route.Rewrite + strings.TrimPrefix(c.Request.URL.Path, route.Prefix)
+			c.Request.URL.RawPath = ""
+		}
 
-	proxy.ErrorHandler = func(rw http.ResponseWriter, req *http.Request, err error) {
-		log.Printf("Monolith proxy error: %v", err)
-		rw.WriteHeader(http.StatusBadGateway)
-		rw.Write([]byte(`{"error": "Gateway: Monolith service unavailable"}`))
+		table.upstreams[route.Upstream].ServeHTTP(c.Writer, c.Request)
 	}
-
-	proxy.ServeHTTP(c.Writer, c.Request)
 }
 
 // HealthCheck provides gateway health status
 func HealthCheck(c *gin.Context) {
-	// Test connection to all services
-	monolithResp, monolithErr := http.Get(MONOLITH_BASE_URL + "/health")
-	authResp, authErr := http.Get(AUTH_SERVICE_URL + "/health")
-	projectResp, projectErr := http.Get(PROJECT_SERVICE_URL + "/health")
-	taskResp, taskErr := http.Get(TASK_SERVICE_URL + "/health")
-
-	var monolithStatus, authStatus, projectStatus, taskStatus string
-
-	// Check monolith status
-	if monolithErr != nil {
-		monolithStatus = "unreachable"
-	} else {
-		monolithResp.Body.Close()
-		if monolithResp.StatusCode == 200 {
-			monolithStatus = "healthy"
-		} else {
-			monolithStatus = "unhealthy"
-		}
-	}
-
-	// Check auth service status
-	if authErr != nil {
-		authStatus = "unreachable"
-	} else {
-		authResp.Body.Close()
-		if authResp.StatusCode == 200 {
-			authStatus = "healthy"
-		} else {
-			authStatus = "unhealthy"
-		}
+	table := current.Load()
+	if table == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"gateway_status": "unconfigured"})
+		return
 	}
 
-	// Check project service status
-	if projectErr != nil {
-		projectStatus = "unreachable"
-	} else {
-		projectResp.Body.Close()
-		if projectResp.StatusCode == 200 {
-			projectStatus = "healthy"
-		} else {
-			projectStatus = "unhealthy"
+	// Test connection to every instance in parallel
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	upstreams := gin.H{}
+	for name, p := range table.upstreams {
+		instances := make([]gin.H, len(p.instances))
+		upstreams[name] = gin.H{"balancer": p.balancer(), "circuit": p.breaker.state(), "instances": instances}
+
+		for i, inst := range p.instances {
+			wg.Add(1)
+			go func(i int, inst *instance) {
+				defer wg.Done()
+				status := "unreachable"
+				if resp, err := probeClient.Get(inst.target.String() + p.healthPath); err == nil {
+					resp.Body.Close()
+					if resp.StatusCode == http.StatusOK {
+						status = "healthy"
+					} else {
+						status = "unhealthy"
+					}
+				}
+
+				mu.Lock()
+				instances[i] = gin.H{
+					"url":       inst.target.String(),
+					"status":    status,
+					"ejected":   inst.ejected.Load(),
+					"in_flight": inst.active.Load(),
+				}
+				mu.Unlock()
+			}(i, inst)
 		}
 	}
+	wg.Wait()
 
-	// Check task service status
-	if taskErr != nil {
-		taskStatus = "unreachable"
-	} else {
-		taskResp.Body.Close()
-		if taskResp.StatusCode == 200 {
-			taskStatus = "healthy"
-		} else {
-			taskStatus = "unhealthy"
+	routing := gin.H{}
+	for _, route := range table.routes {
+		key := route.Prefix
+		if len(route.Methods) > 0 {
+			key = strings.Join(route.Methods, ",") + " " + key
 		}
+		routing[key] = route.Upstream
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"gateway_status":         "healthy",
-		"monolith_status":        monolithStatus,
-		"auth_service_status":    authStatus,
-		"project_service_status": projectStatus,
-		"task_service_status":    taskStatus,
-		"gateway_port":           "8081",
-		"services": gin.H{
-			"monolith":        MONOLITH_BASE_URL,
-			"auth_service":    AUTH_SERVICE_URL,
-			"project_service": PROJECT_SERVICE_URL,
-			"task_service":    TASK_SERVICE_URL,
-		},
-		"message": "API Gateway with full microservices routing",
-		"routing": gin.H{
-			"/auth/*":         "auth-service (port 8082)",
-			"/projects/*":     "project-service (port 8083)",
-			"/tasks/*":        "task-service (port 8084)",
-			"everything_else": "monolith (port 8080)",
-		},
+		"gateway_status": "healthy",
+		"gateway_port":   "8081",
+		"upstreams":      upstreams,
+		"message":        "API Gateway with full microservices routing",
+		"routing":        routing,
 	})
 }
@@ -0,0 +1,101 @@
+package proxy
+
+import (
+	"testing"
+
+	"task-management-gateway/internal/config"
+)
+
+func testConfig(authURL, taskURL string) *config.Config {
+	return &config.Config{
+		Upstreams: map[string]config.Upstream{
+			"auth": {URL: authURL},
+			"task": {URL: taskURL},
+		},
+		Routes: []config.Route{
+			{Prefix: "/auth", Upstream: "auth"},
+			{Prefix: "/tasks", Upstream: "task"},
+		},
+	}
+}
+
+func TestConfigureKeepsUnchangedUpstreams(t *testing.T) {
+	if err := Configure(testConfig("http://auth:8082", "http://task:8084")); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	first := current.Load()
+	auth, task := first.upstreams["auth"], first.upstreams["task"]
+
+	// State that a reload must not reset
+	for range 5 {
+		auth.breaker.failure()
+	}
+	auth.instances[0].ejected.Store(true)
+	auth.instances[0].active.Store(2)
+
+	if err := Configure(testConfig("http://auth:8082", "http://task-v2:8084")); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	second := current.Load()
+
+	if second.upstreams["auth"] != auth {
+		t.Fatal("the unchanged auth upstream got a new pool")
+	}
+	if ok, _ := auth.breaker.allow(); ok {
+		t.Error("the auth circuit closed on reload")
+	}
+	if !auth.instances[0].ejected.Load() || auth.instances[0].active.Load() != 2 {
+		t.Error("the auth instance lost its ejection or in-flight count on reload")
+	}
+
+	if second.upstreams["task"] == task {
+		t.Fatal("the changed task upstream kept its old pool")
+	}
+	if got := second.upstreams["task"].instances[0].target.Host; got != "task-v2:8084" {
+		t.Errorf("task instance = %s, want task-v2:8084", got)
+	}
+	select {
+	case <-task.stop:
+	default:
+		t.Error("the replaced task pool was not closed")
+	}
+	select {
+	case <-auth.stop:
+		t.Error("the kept auth pool was closed")
+	default:
+	}
+}
+
+func TestConfigureClosesRemovedUpstreams(t *testing.T) {
+	if err := Configure(testConfig("http://auth:8082", "http://task:8084")); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	task := current.Load().upstreams["task"]
+
+	cfg := testConfig("http://auth:8082", "")
+	delete(cfg.Upstreams, "task")
+	cfg.Routes = cfg.Routes[:1]
+	if err := Configure(cfg); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	select {
+	case <-task.stop:
+	default:
+		t.Error("the removed task pool was not closed")
+	}
+}
+
+func TestConfigureRejectsInvalidInstances(t *testing.T) {
+	if err := Configure(testConfig("http://auth:8082", "http://task:8084")); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	before := current.Load()
+
+	if err := Configure(testConfig("http://auth:8082", "not a url")); err == nil {
+		t.Fatal("Configure accepted an invalid instance URL")
+	}
+	if current.Load() != before {
+		t.Error("a failed reload replaced the routes")
+	}
+}
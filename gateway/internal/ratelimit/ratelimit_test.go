@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task-management-gateway/internal/auth"
+	"task-management-gateway/internal/config"
+)
+
+// testRouter chains the limiters around authentication as main does
+func testRouter(store Store) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ByIP(store))
+	r.Use(auth.Authenticate(Unauthenticated(store)))
+	r.Use(ByUser(store))
+	r.NoRoute(func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func request(r *gin.Engine, path, authorization string) int {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = "203.0.113.7:40000"
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRefusedRequestsAreThrottled(t *testing.T) {
+	Configure([]config.RateLimit{{Prefix: "/tasks", Requests: 3, Period: "1m", Key: config.KeyByUser}})
+	r := testRouter(NewMemoryStore())
+
+	for i := range 3 {
+		if code := request(r, "/tasks", "Basic bad"); code != http.StatusUnauthorized {
+			t.Fatalf("request %d: status %d, want 401", i+1, code)
+		}
+	}
+	if code := request(r, "/tasks", "Basic bad"); code != http.StatusTooManyRequests {
+		t.Errorf("request past the limit: status %d, want 429", code)
+	}
+}
+
+func TestIPPoliciesApplyBeforeAuthentication(t *testing.T) {
+	Configure([]config.RateLimit{{Prefix: "/admin", Requests: 2, Period: "1m", Key: config.KeyByIP}})
+	r := testRouter(NewMemoryStore())
+
+	for i := range 2 {
+		if code := request(r, "/admin/users", ""); code != http.StatusUnauthorized {
+			t.Fatalf("request %d: status %d, want 401", i+1, code)
+		}
+	}
+	if code := request(r, "/admin/users", ""); code != http.StatusTooManyRequests {
+		t.Errorf("request past the limit: status %d, want 429", code)
+	}
+}
+
+func TestAnonymousRequestsShareIPBucket(t *testing.T) {
+	Configure([]config.RateLimit{{Prefix: "/auth/", Requests: 2, Period: "1m", Key: config.KeyByUser}})
+	r := testRouter(NewMemoryStore())
+
+	// Public paths pass authentication and are limited per IP by ByUser
+	for i := range 2 {
+		if code := request(r, "/auth/login", ""); code != http.StatusOK {
+			t.Fatalf("request %d: status %d, want 200", i+1, code)
+		}
+	}
+	if code := request(r, "/auth/login", ""); code != http.StatusTooManyRequests {
+		t.Errorf("request past the limit: status %d, want 429", code)
+	}
+}
+
+func TestMatchLongestPrefix(t *testing.T) {
+	Configure([]config.RateLimit{
+		{Prefix: "/", Requests: 300},
+		{Prefix: "/tasks", Requests: 120},
+		{Prefix: "/tasks", Methods: []string{"POST"}, Requests: 10},
+	})
+
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/projects", 300},
+		{http.MethodGet, "/tasks/1", 120},
+		{http.MethodPost, "/tasks", 10},
+	}
+	for _, test := range tests {
+		policy, ok := match(test.method, test.path)
+		if !ok || policy.Requests != test.want {
+			t.Errorf("match(%s %s) = %d requests, want %d", test.method, test.path, policy.Requests, test.want)
+		}
+	}
+}
+
+func TestTakeRescalesBucketsWhenLimitChanges(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	take := func(limit Limit) Result {
+		t.Helper()
+		result, err := store.Take(ctx, "key", limit)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		return result
+	}
+
+	// Half of a bucket of 4 is left
+	old := Limit{Burst: 4, Period: time.Hour}
+	take(old)
+	take(old)
+
+	// Shrunk to 2, the bucket is still half full: one token
+	shrunk := Limit{Burst: 2, Period: time.Hour}
+	if result := take(shrunk); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("after shrinking: allowed %v, remaining %d; want allowed, 0", result.Allowed, result.Remaining)
+	}
+	if result := take(shrunk); result.Allowed {
+		t.Error("the shrunk bucket allowed more than its share")
+	}
+
+	// Grown to 10, an empty bucket stays empty rather than keeping the old rate
+	grown := Limit{Burst: 10, Period: time.Second}
+	if result := take(grown); result.Allowed {
+		t.Error("the grown bucket allowed a request before refilling")
+	} else if result.RetryAfter > 200*time.Millisecond {
+		t.Errorf("retry after %v, want the new rate of a token per 100ms", result.RetryAfter)
+	}
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket: Burst tokens, refilled at Burst per Period
+type Limit struct {
+	Burst  int
+	Period time.Duration
+}
+
+// Result is the outcome of taking a token from a bucket
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next token, when not allowed
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. MemoryStore suits a single gateway instance;
+// replicas should share buckets through a Store backed by e.g. Redis.
+// Take applies the limit it is given to an existing bucket even if the
+// bucket was filled under another, as policies change on reload.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	limit   Limit
+}
+
+// MemoryStore is an in-process Store
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// NewMemoryStore creates an empty in-process store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}, swept: time.Now()}
+}
+
+// Take refills the key's bucket for the time elapsed and takes one token
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	capacity := float64(limit.Burst)
+	perToken := limit.Period / time.Duration(limit.Burst)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now, limit: limit}
+		s.buckets[key] = b
+	} else {
+		if b.limit != limit {
+			// The policy changed: keep the bucket as full, relative to
+			// its size, as it was under the old one
+			b.tokens *= capacity / float64(b.limit.Burst)
+			b.limit = limit
+		}
+		elapsed := now.Sub(b.updated)
+		b.tokens = math.Min(capacity, b.tokens+elapsed.Seconds()/perToken.Seconds())
+		b.updated = now
+	}
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) * float64(perToken))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration((capacity - b.tokens) * float64(perToken))
+
+	s.sweep(now)
+	return result, nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.swept) < time.Minute {
+		return
+	}
+	s.swept = now
+
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) > b.limit.Period {
+			delete(s.buckets, key)
+		}
+	}
+}
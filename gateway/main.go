@@ -3,23 +3,56 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
+	"strings"
+	"task-management-gateway/internal/auth"
+	"task-management-gateway/internal/config"
 	"task-management-gateway/internal/proxy"
+	"task-management-gateway/internal/ratelimit"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// Load the route table, falling back to the built-in routes
+	routesFile := os.Getenv("GATEWAY_ROUTES_FILE")
+	cfg := config.Default()
+	if routesFile != "" {
+		loaded, err := config.Load(routesFile)
+		if err != nil {
+			log.Fatal("Failed to load route config:", err)
+		}
+		cfg = loaded
+	}
+	if err := proxy.Configure(cfg); err != nil {
+		log.Fatal("Invalid route config:", err)
+	}
+	ratelimit.Configure(cfg.RateLimits)
+
+	// Hot-reload the route file on change
+	if routesFile != "" {
+		go config.Watch(routesFile, 2*time.Second, func(cfg *config.Config) {
+			if err := proxy.Configure(cfg); err != nil {
+				log.Printf("Route config rejected, keeping previous routes: %v", err)
+				return
+			}
+			ratelimit.Configure(cfg.RateLimits)
+		})
+	}
+
 	// Set Gin to release mode for cleaner output
 	gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
 
-	// Add logging middleware
+	// Add logging middleware. The URI is logged as forwarded, so tokens
+	// passed in the query string of event streams stay out of the logs.
 	r.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
 		return fmt.Sprintf("[GATEWAY] %s - %s %s %d %s\n",
 			param.TimeStamp.Format("15:04:05"),
 			param.Method,
-			param.Path,
+			param.Request.URL.RequestURI(),
 			param.StatusCode,
 			param.Latency,
 		)
@@ -28,6 +61,22 @@ func main() {
 	// Add recovery middleware
 	r.Use(gin.Recovery())
 
+	// Only trust X-Forwarded-For from known proxies, so clients can't dodge IP rate limits
+	if err := r.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatal("Invalid TRUSTED_PROXIES:", err)
+	}
+
+	// Throttle per client IP before authenticating, so requests with bad
+	// tokens are throttled too, then per user (or IP for anonymous requests)
+	limits := ratelimit.NewMemoryStore()
+	r.Use(ratelimit.ByIP(limits))
+
+	// Validate tokens once at the edge and forward the verified identity;
+	// refused requests count against the client IP
+	r.Use(auth.Authenticate(ratelimit.Unauthenticated(limits)))
+
+	r.Use(ratelimit.ByUser(limits))
+
 	// Gateway health check (different from monolith health check)
 	r.GET("/gateway/health", proxy.HealthCheck)
 
@@ -35,10 +84,25 @@ func main() {
 	r.NoRoute(proxy.SmartProxy())
 
 	log.Println("🚀 API Gateway starting on port 8081")
-	log.Println("📡 Forwarding all requests to monolith at http://localhost:8080")
+	if routesFile != "" {
+		log.Printf("📡 Routing with %s (%d routes, hot-reloaded)", routesFile, len(cfg.Routes))
+	} else {
+		log.Printf("📡 Routing with built-in routes (%d routes)", len(cfg.Routes))
+	}
 	log.Println("🔍 Gateway health check: http://localhost:8081/gateway/health")
 
 	if err := r.Run(":8081"); err != nil {
 		log.Fatal("Failed to start gateway:", err)
 	}
 }
+
+// trustedProxies reads TRUSTED_PROXIES, a comma-separated list of proxy IPs or CIDRs
+func trustedProxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
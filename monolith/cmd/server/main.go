@@ -25,16 +25,31 @@ package main
 import (
 	"log"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/P4rz1val22/task-management-api/internal/database"
 	"github.com/P4rz1val22/task-management-api/internal/handlers"
 	"github.com/P4rz1val22/task-management-api/internal/middleware"
+	"github.com/P4rz1val22/task-management-api/internal/outbox"
+	"github.com/P4rz1val22/task-management-api/internal/reminders"
+	"github.com/P4rz1val22/task-management-api/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
 	database.Connect()
 
+	emailService := services.NewEmailService()
+
+	// Email due date reminders and daily digests in the background
+	reminders.NewScheduler(emailService).Start(reminderInterval())
+
+	// Publish domain events from the outbox; task emails are sent by a subscriber
+	broker := outbox.NewMemoryBroker()
+	broker.Subscribe(emailService.HandleTaskEvent, outbox.TaskCreated, outbox.TaskUpdated)
+	outbox.NewRelay(database.DB, broker).Start(outboxInterval())
+
 	r := gin.Default()
 
 	//r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -60,6 +75,8 @@ func main() {
 	{
 		users.GET("/me", handlers.GetCurrentUser)
 		users.PUT("/me", handlers.UpdateCurrentUser)
+		users.GET("/me/notifications", handlers.GetNotificationPreferences)
+		users.PUT("/me/notifications", handlers.UpdateNotificationPreferences)
 	}
 
 	projects := r.Group("/projects")
@@ -70,6 +87,8 @@ func main() {
 		projects.GET("/:id", handlers.GetProjectByID)
 		projects.PUT("/:id", handlers.UpdateProject)
 		projects.DELETE("/:id", handlers.DeleteProject)
+		projects.GET("/:id/workflow", handlers.GetProjectWorkflow)
+		projects.PUT("/:id/workflow", handlers.UpdateProjectWorkflow)
 	}
 
 	tasks := r.Group("/tasks")
@@ -79,6 +98,8 @@ func main() {
 		tasks.GET("", handlers.GetTasks)
 		tasks.GET("/:id", handlers.GetTaskByID)
 		tasks.PUT("/:id", handlers.UpdateTask)
+		tasks.PATCH("/:id/assign", handlers.AssignTask)
+		tasks.GET("/:id/history", handlers.GetTaskHistory)
 		tasks.DELETE("/:id", handlers.DeleteTask)
 	}
 
@@ -88,3 +109,22 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// reminderInterval reads how often due dates are checked from
+// REMINDER_INTERVAL (e.g. 5m, 1h), defaulting to every 10 minutes
+func reminderInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("REMINDER_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 10 * time.Minute
+}
+
+// outboxInterval reads how often the outbox is polled from OUTBOX_INTERVAL
+// (e.g. 1s, 30s), defaulting to every 5 seconds. Events are published right
+// after their change commits regardless; polling picks up retries.
+func outboxInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("OUTBOX_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 5 * time.Second
+}
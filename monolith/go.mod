@@ -57,4 +57,7 @@ require (
 	golang.org/x/tools v0.35.0 // indirect
 	google.golang.org/protobuf v1.34.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
+	task-management-pkg v0.0.0
 )
+
+replace task-management-pkg => ../pkg
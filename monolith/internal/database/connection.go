@@ -29,7 +29,20 @@ func Connect() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
-	err = DB.AutoMigrate(&models.User{}, &models.Project{}, &models.Task{})
+	err = DB.AutoMigrate(&models.User{}, &models.Project{}, &models.Task{}, &models.RevokedToken{}, &models.TaskEvent{}, &models.Reminder{}, &models.OutboxEvent{}, &models.PendingNotification{})
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Projects created before workflows existed get the default one
+	err = DB.Model(&models.Project{}).Where("workflow IS NULL").UpdateColumn("workflow", models.DefaultWorkflow()).Error
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Events recorded before they carried a project get their task's
+	err = DB.Exec("UPDATE task_events SET project_id = tasks.project_id FROM tasks " +
+		"WHERE tasks.id = task_events.task_id AND task_events.project_id IS NULL").Error
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
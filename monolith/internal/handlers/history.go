@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/P4rz1val22/task-management-api/internal/database"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+)
+
+// recordTaskEvent appends an entry to a task's audit trail
+func recordTaskEvent(tx *gorm.DB, task models.Task, actorID uint, action string, changes models.FieldChanges) error {
+	return tx.Create(&models.TaskEvent{
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		ActorID:   actorID,
+		Action:    action,
+		Changes:   changes,
+	}).Error
+}
+
+// @Summary    Get task history
+// @Description Get the audit trail of a task, oldest first, including tasks that have since been deleted
+// @Tags       tasks
+// @Produce    json
+// @Security   BearerAuth
+// @Param      id     path     int     true   "Task ID"
+// @Param      field  query    string  false  "Only events that changed this field (e.g. status)"
+// @Success    200    {object} map[string]interface{}
+// @Failure    401    {object} map[string]interface{}
+// @Failure    404    {object} map[string]interface{}
+// @Router     /tasks/{id}/history [get]
+func GetTaskHistory(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	taskID := c.Param("id")
+
+	var task models.Task
+	if err := database.DB.Unscoped().Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if !canAccessTask(task, userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	query := database.DB.Preload("Actor").Where("task_id = ?", task.ID)
+	if field := c.Query("field"); field != "" {
+		filter, _ := json.Marshal([]gin.H{{"field": field}})
+		query = query.Where("changes @> ?", string(filter))
+	}
+
+	var events []models.TaskEvent
+	if err := query.Order("created_at, id").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch task history"})
+		return
+	}
+
+	history := make([]gin.H, 0, len(events))
+	for _, event := range events {
+		history = append(history, gin.H{
+			"id":     event.ID,
+			"action": event.Action,
+			"actor": gin.H{
+				"id":   event.ActorID,
+				"name": userName(event.Actor),
+			},
+			"changes":    event.Changes,
+			"created_at": event.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"task_id": task.ID,
+		"history": history,
+	})
+}
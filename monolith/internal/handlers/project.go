@@ -3,8 +3,12 @@ package handlers
 import (
 	"github.com/P4rz1val22/task-management-api/internal/database"
 	"github.com/P4rz1val22/task-management-api/internal/models"
+	"github.com/P4rz1val22/task-management-api/internal/outbox"
+	"github.com/P4rz1val22/task-management-api/internal/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"net/http"
+	"task-management-pkg/pagination"
 )
 
 type ProjectRequest struct {
@@ -12,6 +16,26 @@ type ProjectRequest struct {
 	Description string `json:"description"`
 }
 
+// projectChanges lists the project details that differ between two versions
+func projectChanges(before, after models.Project) []services.ChangeDetail {
+	var changes []services.ChangeDetail
+	if before.Name != after.Name {
+		changes = append(changes, services.ChangeDetail{Field: "name", From: before.Name, To: after.Name})
+	}
+	if before.Description != after.Description {
+		changes = append(changes, services.ChangeDetail{Field: "description", From: before.Description, To: after.Description})
+	}
+	return changes
+}
+
+// projectSortColumns are the fields GET /projects can be sorted by
+var projectSortColumns = map[string]string{
+	"id":         "projects.id",
+	"name":       "projects.name",
+	"created_at": "projects.created_at",
+	"updated_at": "projects.updated_at",
+}
+
 // @Summary		Create a new project
 // @Description	Create a new project owned by the authenticated user
 // @Tags			projects
@@ -54,12 +78,20 @@ func CreateProject(c *gin.Context) {
 		Name:        req.Name,
 		Description: req.Description,
 		OwnerID:     userID,
+		Workflow:    models.DefaultWorkflow(),
 	}
 
-	if err := database.DB.Create(&project).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, outbox.ProjectCreated, project.ID, userID, services.NewProjectEvent(project, nil))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
 		return
 	}
+	outbox.Wake()
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Project created successfully",
@@ -79,6 +111,11 @@ func CreateProject(c *gin.Context) {
 // @Tags       projects
 // @Produce    json
 // @Security   BearerAuth
+// @Param      limit   query    int     false  "Page size (default 50, max 200)"
+// @Param      cursor  query    string  false  "Cursor from the previous page's next_cursor"
+// @Param      offset  query    int     false  "Rows to skip, instead of a cursor"
+// @Param      sort    query    string  false  "Comma-separated sort fields, - for descending (e.g. -created_at)"
+// @Failure    400   {object}    map[string]interface{}
 // @Success    200   {object}    map[string]interface{}
 // @Failure    401   {object}    map[string]interface{}
 // @Failure    500   {object}    map[string]interface{}
@@ -86,12 +123,30 @@ func CreateProject(c *gin.Context) {
 func GetProjects(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
+	page, err := pagination.Parse(c, "projects", projectSortColumns, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := database.DB.Model(&models.Project{}).Where("owner_id = ?", userID).Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+
 	var projects []models.Project
-	if err := database.DB.Preload("Owner").Where("owner_id = ?", userID).Find(&projects).Error; err != nil {
+	if err := page.Apply(query.Preload("Owner")).Find(&projects).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
 		return
 	}
 
+	hasMore := len(projects) > page.Limit
+	if hasMore {
+		projects = projects[:page.Limit]
+	}
+
 	var projectList []gin.H
 	for _, project := range projects {
 		projectList = append(projectList, gin.H{
@@ -104,8 +159,16 @@ func GetProjects(c *gin.Context) {
 		})
 	}
 
+	var lastID uint
+	if len(projects) > 0 {
+		lastID = projects[len(projects)-1].ID
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"projects": projectList,
+		"projects":    projectList,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": pagination.NextCursor(hasMore, lastID),
 	})
 }
 
@@ -138,6 +201,7 @@ func GetProjectByID(c *gin.Context) {
 			"owner_id":    project.OwnerID,
 			"owner":       project.Owner.Name,
 			"task_count":  len(project.Tasks),
+			"workflow":    project.Workflow,
 			"created_at":  project.CreatedAt,
 			"updated_at":  project.UpdatedAt,
 		},
@@ -180,13 +244,25 @@ func UpdateProject(c *gin.Context) {
 		return
 	}
 
+	original := project
 	project.Name = req.Name
 	project.Description = req.Description
 
-	if err := database.DB.Save(&project).Error; err != nil {
+	changes := projectChanges(original, project)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&project).Error; err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return outbox.Enqueue(tx, outbox.ProjectUpdated, project.ID, userID, services.NewProjectEvent(project, changes))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
 		return
 	}
+	outbox.Wake()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Project updated successfully",
@@ -232,10 +308,17 @@ func DeleteProject(c *gin.Context) {
 	}
 
 	// Safe to delete - no tasks exist
-	if err := database.DB.Delete(&project).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&project).Error; err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, outbox.ProjectDeleted, project.ID, userID, services.NewProjectEvent(project, nil))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
 		return
 	}
+	outbox.Wake()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Project deleted successfully",
@@ -1,16 +1,22 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"github.com/P4rz1val22/task-management-api/internal/database"
 	"github.com/P4rz1val22/task-management-api/internal/models"
+	"github.com/P4rz1val22/task-management-api/internal/outbox"
 	"github.com/P4rz1val22/task-management-api/internal/services"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"net/http"
+	"strconv"
+	"strings"
+	"task-management-pkg/pagination"
 	"time"
 )
 
-var emailService = services.NewEmailService()
-
 type TaskRequest struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description"`
@@ -19,36 +25,119 @@ type TaskRequest struct {
 	Estimate    string `json:"estimate"`
 	Status      string `json:"status"`
 	DueDate     string `json:"due_date"`
+	AssigneeID  *uint  `json:"assignee_id"`
 }
 
-func isValidStatus(status string) bool {
-	validStatuses := []string{"Not Started", "In Progress", "Done", "Blocked"}
-	for _, v := range validStatuses {
-		if v == status {
-			return true
-		}
+type AssignRequest struct {
+	AssigneeID *uint `json:"assignee_id"`
+}
+
+// validateTaskFields checks a task's status, priority and estimate against
+// its project's workflow, writing the 400 response itself. Empty values are
+// not checked.
+func validateTaskFields(c *gin.Context, workflow models.Workflow, status, priority, estimate string) bool {
+	if status != "" && !workflow.HasStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: " + strings.Join(workflow.Statuses, ", ")})
+		return false
+	}
+	if priority != "" && !workflow.HasPriority(priority) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: " + strings.Join(workflow.Priorities, ", ")})
+		return false
 	}
-	return false
+	if estimate != "" && !workflow.HasEstimate(estimate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: " + strings.Join(workflow.Estimates, ", ")})
+		return false
+	}
+	return true
 }
 
-func isValidPriority(priority string) bool {
-	validPriorities := []string{"Low", "Medium", "High", "Urgent"}
-	for _, v := range validPriorities {
-		if v == priority {
-			return true
-		}
+// errWorkflowChanged is returned by lockWorkflow when the task's values left
+// its project's workflow since they were validated
+var errWorkflowChanged = errors.New("workflow changed")
+
+// lockWorkflow locks the task's project row for the rest of the transaction
+// and checks the task's status, priority and estimate are still in its
+// workflow. Workflow updates lock the row for update before checking which
+// values tasks use, so they wait for this transaction and see its task.
+func lockWorkflow(tx *gorm.DB, task models.Task) error {
+	var project models.Project
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&project, task.ProjectID).Error; err != nil {
+		return err
 	}
-	return false
+	workflow := project.Workflow
+	if !workflow.HasStatus(task.Status) ||
+		(task.Priority != "" && !workflow.HasPriority(task.Priority)) ||
+		(task.Estimate != "" && !workflow.HasEstimate(task.Estimate)) {
+		return errWorkflowChanged
+	}
+	return nil
 }
 
-func isValidEstimate(estimate string) bool {
-	validEstimates := []string{"S", "M", "L", "XL"}
-	for _, v := range validEstimates {
-		if v == estimate {
-			return true
-		}
+// workflowChanged answers a write that lost a race with a workflow update
+func workflowChanged(c *gin.Context) {
+	c.JSON(http.StatusConflict, gin.H{"error": "The project's workflow changed meanwhile. Check the task's status, priority and estimate and try again"})
+}
+
+// workflowRank is the 1-based position of a task's value in the named list of
+// its project's workflow, or 0 when the value isn't in the list
+func workflowRank(column, list string) string {
+	return fmt.Sprintf("COALESCE((SELECT s.n FROM projects p, jsonb_array_elements_text(p.workflow->'%s') WITH ORDINALITY AS s(value, n) "+
+		"WHERE p.id = tasks.project_id AND s.value = tasks.%s), 0)", list, column)
+}
+
+// taskSortColumns are the fields GET /tasks can be sorted by. Status, priority
+// and estimate sort in their project's workflow order rather than
+// alphabetically, and tasks without a due date sort after those with one.
+var taskSortColumns = map[string]string{
+	"id":         "tasks.id",
+	"title":      "tasks.title",
+	"status":     workflowRank("status", "statuses"),
+	"priority":   workflowRank("priority", "priorities"),
+	"estimate":   workflowRank("estimate", "estimates"),
+	"due_date":   "COALESCE(tasks.due_date, 'infinity')",
+	"created_at": "tasks.created_at",
+	"updated_at": "tasks.updated_at",
+}
+
+func assigneeExists(assigneeID uint) bool {
+	var assignee models.User
+	return database.DB.First(&assignee, assigneeID).Error == nil
+}
+
+// parseQueryID reads a numeric ID from the query string, answering 400 when
+// it isn't one
+func parseQueryID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Query(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + ". Use a positive number"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// canAccessTask allows the owner of the task's project and the task's
+// assignee to read it. Only the owner may change it, except for its status.
+func canAccessTask(task models.Task, userID uint) bool {
+	return task.Project.OwnerID == userID || (task.AssigneeID != nil && *task.AssigneeID == userID)
+}
+
+// onlyStatusChanged reports whether an update request leaves everything but
+// the task's status as it is
+func onlyStatusChanged(task models.Task, req TaskRequest) bool {
+	dueDate := ""
+	if task.DueDate != nil {
+		dueDate = task.DueDate.Format("2006-01-02")
+	}
+	sameAssignee := req.AssigneeID == nil || (task.AssigneeID != nil && *req.AssigneeID == *task.AssigneeID)
+	return req.Title == task.Title && req.Description == task.Description && req.ProjectID == task.ProjectID &&
+		req.Priority == task.Priority && req.Estimate == task.Estimate && req.DueDate == dueDate && sameAssignee
+}
+
+func userName(user *models.User) string {
+	if user == nil {
+		return ""
 	}
-	return false
+	return user.Name
 }
 
 // @Summary    Create a new task
@@ -78,17 +167,11 @@ func CreateTask(c *gin.Context) {
 		return
 	}
 
-	if req.Status != "" && !isValidStatus(req.Status) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: Not Started, In Progress, Done, Blocked"})
-		return
-	}
-	if req.Priority != "" && !isValidPriority(req.Priority) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: Low, Medium, High, Urgent"})
+	if !validateTaskFields(c, project.Workflow, req.Status, req.Priority, req.Estimate) {
 		return
 	}
-	if req.Estimate != "" && !isValidEstimate(req.Estimate) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: S, M, L, XL"})
-		return
+	if req.Status == "" {
+		req.Status = project.Workflow.InitialStatus()
 	}
 
 	var dueDate *time.Time
@@ -101,11 +184,20 @@ func CreateTask(c *gin.Context) {
 		dueDate = &parsed
 	}
 
+	assigneeID := &userID
+	if req.AssigneeID != nil {
+		if !assigneeExists(*req.AssigneeID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee not found"})
+			return
+		}
+		assigneeID = req.AssigneeID
+	}
+
 	task := models.Task{
 		Title:       req.Title,
 		Description: req.Description,
 		ProjectID:   req.ProjectID,
-		AssigneeID:  &userID,
+		AssigneeID:  assigneeID,
 		CreatorID:   &userID,
 		Status:      req.Status,
 		Estimate:    req.Estimate,
@@ -113,18 +205,27 @@ func CreateTask(c *gin.Context) {
 		DueDate:     dueDate,
 	}
 
-	if err := database.DB.Create(&task).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockWorkflow(tx, task); err != nil {
+			return err
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		if err := recordTaskEvent(tx, task, userID, models.TaskCreated, models.TaskChanges(models.Task{}, task)); err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, outbox.TaskCreated, task.ID, userID, services.NewTaskEvent(task, nil))
+	})
+	if errors.Is(err, errWorkflowChanged) {
+		workflowChanged(c)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
 		return
 	}
-
-	go func() {
-		// Get user email for notification
-		var user models.User
-		if err := database.DB.First(&user, userID).Error; err == nil {
-			emailService.SendTaskCreatedNotification(task, user.Email)
-		}
-	}()
+	outbox.Wake()
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Task created successfully",
@@ -133,6 +234,7 @@ func CreateTask(c *gin.Context) {
 			"title":       task.Title,
 			"description": task.Description,
 			"project_id":  task.ProjectID,
+			"assignee_id": task.AssigneeID,
 			"status":      task.Status,
 			"estimate":    task.Estimate,
 			"priority":    task.Priority,
@@ -143,16 +245,22 @@ func CreateTask(c *gin.Context) {
 }
 
 // @Summary    Get all tasks
-// @Description Get list of tasks assigned to authenticated user with optional filtering
+// @Description Get list of tasks in projects owned by or assigned to authenticated user with optional filtering
 // @Tags       tasks
 // @Produce    json
 // @Security   BearerAuth
 // @Param      project_id    query    int     false  "Filter by project ID"
+// @Param      assignee_id   query    int     false  "Filter by assignee ID"
+// @Param      creator_id    query    int     false  "Filter by creator ID"
 // @Param      status        query    string  false  "Filter by status"
 // @Param      priority      query    string  false  "Filter by priority"
 // @Param      estimate      query    string  false  "Filter by estimate"
 // @Param      due_date_from query    string  false  "Filter tasks due after date (YYYY-MM-DD)"
 // @Param      due_date_to   query    string  false  "Filter tasks due before date (YYYY-MM-DD)"
+// @Param      limit         query    int     false  "Page size (default 50, max 200)"
+// @Param      cursor        query    string  false  "Cursor from the previous page's next_cursor"
+// @Param      offset        query    int     false  "Rows to skip, instead of a cursor"
+// @Param      sort          query    string  false  "Comma-separated sort fields, - for descending (e.g. due_date,-priority)"
 // @Success    200           {object} map[string]interface{}
 // @Failure    400           {object} map[string]interface{}
 // @Failure    401           {object} map[string]interface{}
@@ -160,36 +268,61 @@ func CreateTask(c *gin.Context) {
 func GetTasks(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	query := database.DB.Preload("Project").Preload("Creator").Preload("Assignee").Where("assignee_id = ?", userID)
+	page, err := pagination.Parse(c, "tasks", taskSortColumns, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ownedProjects := database.DB.Model(&models.Project{}).Select("id").Where("owner_id = ?", userID)
+	query := database.DB.Model(&models.Task{}).Where("project_id IN (?) OR assignee_id = ?", ownedProjects, userID)
 
+	// A project filter's workflow validates the status, priority and estimate filters
+	var workflow *models.Workflow
 	if projectID := c.Query("project_id"); projectID != "" {
 		var project models.Project
 		if err := database.DB.Where("id = ? AND owner_id = ?", projectID, userID).First(&project).Error; err != nil {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or access denied"})
 			return
 		}
+		workflow = &project.Workflow
 		query = query.Where("project_id = ?", projectID)
 	}
 
+	if c.Query("assignee_id") != "" {
+		assigneeID, ok := parseQueryID(c, "assignee_id")
+		if !ok {
+			return
+		}
+		query = query.Where("assignee_id = ?", assigneeID)
+	}
+	if c.Query("creator_id") != "" {
+		creatorID, ok := parseQueryID(c, "creator_id")
+		if !ok {
+			return
+		}
+		query = query.Where("creator_id = ?", creatorID)
+	}
+
 	if status := c.Query("status"); status != "" {
-		if !isValidStatus(status) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: Not Started, In Progress, Done, Blocked"})
+		if workflow != nil && !workflow.HasStatus(status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: " + strings.Join(workflow.Statuses, ", ")})
 			return
 		}
 		query = query.Where("status = ?", status)
 	}
 
 	if priority := c.Query("priority"); priority != "" {
-		if !isValidPriority(priority) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: Low, Medium, High, Urgent"})
+		if workflow != nil && !workflow.HasPriority(priority) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: " + strings.Join(workflow.Priorities, ", ")})
 			return
 		}
 		query = query.Where("priority = ?", priority)
 	}
 
 	if estimate := c.Query("estimate"); estimate != "" {
-		if !isValidEstimate(estimate) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: S, M, L, XL"})
+		if workflow != nil && !workflow.HasEstimate(estimate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: " + strings.Join(workflow.Estimates, ", ")})
 			return
 		}
 		query = query.Where("estimate = ?", estimate)
@@ -212,12 +345,24 @@ func GetTasks(c *gin.Context) {
 		query = query.Where("due_date <= ?", date)
 	}
 
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
+		return
+	}
+
 	var tasks []models.Task
-	if err := query.Find(&tasks).Error; err != nil {
+	if err := page.Apply(query.Preload("Project").Preload("Creator").Preload("Assignee")).Find(&tasks).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
 		return
 	}
 
+	hasMore := len(tasks) > page.Limit
+	if hasMore {
+		tasks = tasks[:page.Limit]
+	}
+
 	var taskList []gin.H
 	for _, task := range tasks {
 		taskList = append(taskList, gin.H{
@@ -229,7 +374,7 @@ func GetTasks(c *gin.Context) {
 				"name": task.Project.Name,
 			},
 			"creator":    task.Creator.Name,
-			"assignee":   task.Assignee.Name,
+			"assignee":   userName(task.Assignee),
 			"status":     task.Status,
 			"priority":   task.Priority,
 			"estimate":   task.Estimate,
@@ -239,13 +384,21 @@ func GetTasks(c *gin.Context) {
 		})
 	}
 
+	var lastID uint
+	if len(tasks) > 0 {
+		lastID = tasks[len(tasks)-1].ID
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"tasks": taskList,
+		"tasks":       taskList,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": pagination.NextCursor(hasMore, lastID),
 	})
 }
 
 // @Summary    Get task by ID
-// @Description Get detailed information about a specific task assigned to authenticated user
+// @Description Get detailed information about a specific task in a project owned by or assigned to authenticated user
 // @Tags       tasks
 // @Produce    json
 // @Security   BearerAuth
@@ -259,12 +412,12 @@ func GetTaskByID(c *gin.Context) {
 	taskID := c.Param("id")
 
 	var task models.Task
-	if err := database.DB.Preload("Project").Preload("Creator").Preload("Assignee").Where("id = ? AND assignee_id = ?", taskID, userID).First(&task).Error; err != nil {
+	if err := database.DB.Preload("Project").Preload("Creator").Preload("Assignee").Where("id = ?", taskID).First(&task).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
 
-	if task.Project.OwnerID != userID {
+	if !canAccessTask(task, userID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
@@ -279,7 +432,7 @@ func GetTaskByID(c *gin.Context) {
 				"name": task.Project.Name,
 			},
 			"creator":    task.Creator.Name,
-			"assignee":   task.Assignee.Name,
+			"assignee":   userName(task.Assignee),
 			"status":     task.Status,
 			"priority":   task.Priority,
 			"estimate":   task.Estimate,
@@ -291,7 +444,7 @@ func GetTaskByID(c *gin.Context) {
 }
 
 // @Summary    Update task by ID
-// @Description Update a specific task in a project owned by authenticated user
+// @Description Update a specific task in a project owned by the authenticated user. Its assignee may only change its status.
 // @Tags       tasks
 // @Accept     json
 // @Produce    json
@@ -301,6 +454,7 @@ func GetTaskByID(c *gin.Context) {
 // @Success    200     {object} map[string]interface{}
 // @Failure    400     {object} map[string]interface{}
 // @Failure    401     {object} map[string]interface{}
+// @Failure    403     {object} map[string]interface{}
 // @Failure    404     {object} map[string]interface{}
 // @Router     /tasks/{id} [put]
 func UpdateTask(c *gin.Context) {
@@ -314,15 +468,19 @@ func UpdateTask(c *gin.Context) {
 	}
 
 	var task models.Task
-	if err := database.DB.Preload("Project").Where("id = ? AND assignee_id = ?", taskID, userID).First(&task).Error; err != nil {
+	if err := database.DB.Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
 
-	if task.Project.OwnerID != userID {
+	if !canAccessTask(task, userID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
+	if task.Project.OwnerID != userID && !onlyStatusChanged(task, req) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change more than the task's status"})
+		return
+	}
 
 	if req.ProjectID != task.ProjectID {
 		var newProject models.Project
@@ -330,18 +488,30 @@ func UpdateTask(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Target project not found or access denied"})
 			return
 		}
+		task.Project = newProject
 	}
 
-	if req.Status != "" && !isValidStatus(req.Status) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: Not Started, In Progress, Done, Blocked"})
-		return
+	if req.AssigneeID != nil {
+		if !assigneeExists(*req.AssigneeID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee not found"})
+			return
+		}
+		task.AssigneeID = req.AssigneeID
 	}
-	if req.Priority != "" && !isValidPriority(req.Priority) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: Low, Medium, High, Urgent"})
+
+	// An omitted status keeps the current one; moves must follow the workflow
+	workflow := task.Project.Workflow
+	if req.Status == "" {
+		req.Status = task.Status
+	}
+	if !validateTaskFields(c, workflow, req.Status, req.Priority, req.Estimate) {
 		return
 	}
-	if req.Estimate != "" && !isValidEstimate(req.Estimate) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: S, M, L, XL"})
+	if req.ProjectID == task.ProjectID && !workflow.CanTransition(task.Status, req.Status) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   fmt.Sprintf("Cannot move a task from %s to %s", task.Status, req.Status),
+			"allowed": workflow.NextStatuses(task.Status),
+		})
 		return
 	}
 
@@ -355,10 +525,7 @@ func UpdateTask(c *gin.Context) {
 		dueDate = &parsed
 	}
 
-	originalTitle := task.Title
-	originalStatus := task.Status
-	originalPriority := task.Priority
-	originalEstimate := task.Estimate
+	original := task
 
 	task.Title = req.Title
 	task.Description = req.Description
@@ -368,49 +535,31 @@ func UpdateTask(c *gin.Context) {
 	task.Estimate = req.Estimate
 	task.DueDate = dueDate
 
-	if err := database.DB.Save(&task).Error; err != nil {
+	changes := models.TaskChanges(original, task)
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockWorkflow(tx, task); err != nil {
+			return err
+		}
+		if err := tx.Save(&task).Error; err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := recordTaskEvent(tx, task, userID, models.TaskUpdated, changes); err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, outbox.TaskUpdated, task.ID, userID, services.NewTaskEvent(task, changes))
+	})
+	if errors.Is(err, errWorkflowChanged) {
+		workflowChanged(c)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
 		return
 	}
-
-	// In your UpdateTask function, replace the goroutine section:
-	go func() {
-		var user models.User
-		if err := database.DB.First(&user, userID).Error; err == nil {
-			var changes []services.ChangeDetail
-			if originalTitle != task.Title {
-				changes = append(changes, services.ChangeDetail{
-					Field: "Title",
-					From:  originalTitle,
-					To:    task.Title,
-				})
-			}
-			if originalStatus != task.Status {
-				changes = append(changes, services.ChangeDetail{
-					Field: "Status",
-					From:  originalStatus,
-					To:    task.Status,
-				})
-			}
-			if originalPriority != task.Priority {
-				changes = append(changes, services.ChangeDetail{
-					Field: "Priority",
-					From:  originalPriority,
-					To:    task.Priority,
-				})
-			}
-
-			if originalEstimate != task.Estimate {
-				changes = append(changes, services.ChangeDetail{
-					Field: "Estimate",
-					From:  originalEstimate,
-					To:    task.Estimate,
-				})
-			}
-
-			emailService.SendTaskUpdatedNotification(task, user.Email, changes)
-		}
-	}()
+	outbox.Wake()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Task updated successfully",
@@ -419,6 +568,7 @@ func UpdateTask(c *gin.Context) {
 			"title":       task.Title,
 			"description": task.Description,
 			"project_id":  task.ProjectID,
+			"assignee_id": task.AssigneeID,
 			"status":      task.Status,
 			"priority":    task.Priority,
 			"estimate":    task.Estimate,
@@ -428,6 +578,83 @@ func UpdateTask(c *gin.Context) {
 	})
 }
 
+// @Summary    Assign task
+// @Description Assign a task in a project owned by the authenticated user to another user, or unassign it with a null assignee_id
+// @Tags       tasks
+// @Accept     json
+// @Produce    json
+// @Security   BearerAuth
+// @Param      id      path    int            true   "Task ID"
+// @Param      assign  body    AssignRequest  true   "Assignee data"
+// @Success    200     {object} map[string]interface{}
+// @Failure    400     {object} map[string]interface{}
+// @Failure    401     {object} map[string]interface{}
+// @Failure    403     {object} map[string]interface{}
+// @Failure    404     {object} map[string]interface{}
+// @Router     /tasks/{id}/assign [patch]
+func AssignTask(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	taskID := c.Param("id")
+
+	var req AssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var task models.Task
+	if err := database.DB.Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if !canAccessTask(task, userID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if task.Project.OwnerID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can assign this task"})
+		return
+	}
+
+	if req.AssigneeID != nil && !assigneeExists(*req.AssigneeID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee not found"})
+		return
+	}
+
+	assigned := task
+	assigned.AssigneeID = req.AssigneeID
+	changes := models.TaskChanges(task, assigned)
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&task).Update("assignee_id", req.AssigneeID).Error; err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		if err := recordTaskEvent(tx, task, userID, models.TaskUpdated, changes); err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, outbox.TaskUpdated, task.ID, userID, services.NewTaskEvent(assigned, changes))
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign task"})
+		return
+	}
+	outbox.Wake()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task assigned successfully",
+		"task": gin.H{
+			"id":          task.ID,
+			"title":       task.Title,
+			"assignee_id": req.AssigneeID,
+			"updated_at":  task.UpdatedAt,
+		},
+	})
+}
+
 // @Summary    Delete task by ID
 // @Description Delete a specific task in a project owned by authenticated user
 // @Tags       tasks
@@ -442,7 +669,7 @@ func DeleteTask(c *gin.Context) {
 	taskID := c.Param("id")
 
 	var task models.Task
-	if err := database.DB.Preload("Project").Where("id = ? AND assignee_id = ?", taskID, userID).First(&task).Error; err != nil {
+	if err := database.DB.Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
@@ -452,10 +679,20 @@ func DeleteTask(c *gin.Context) {
 		return
 	}
 
-	if err := database.DB.Delete(&task).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&task).Error; err != nil {
+			return err
+		}
+		if err := recordTaskEvent(tx, task, userID, models.TaskDeleted, models.TaskChanges(task, models.Task{})); err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, outbox.TaskDeleted, task.ID, userID, services.NewTaskEvent(task, nil))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
 		return
 	}
+	outbox.Wake()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Task deleted successfully",
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/P4rz1val22/task-management-api/internal/models"
+)
+
+func TestOnlyStatusChanged(t *testing.T) {
+	due := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	assignee, other := uint(2), uint(3)
+	task := models.Task{Title: "Ship", Description: "v1", ProjectID: 1, Priority: "high", Estimate: "M", Status: "todo", DueDate: &due, AssigneeID: &assignee}
+	unchanged := TaskRequest{Title: "Ship", Description: "v1", ProjectID: 1, Priority: "high", Estimate: "M", Status: "todo", DueDate: "2025-03-01"}
+
+	tests := []struct {
+		name string
+		edit func(*TaskRequest)
+		want bool
+	}{
+		{"nothing", func(*TaskRequest) {}, true},
+		{"status", func(r *TaskRequest) { r.Status = "done" }, true},
+		{"same assignee", func(r *TaskRequest) { r.AssigneeID = &assignee }, true},
+		{"title", func(r *TaskRequest) { r.Title = "Ship it" }, false},
+		{"description", func(r *TaskRequest) { r.Description = "" }, false},
+		{"project", func(r *TaskRequest) { r.ProjectID = 9 }, false},
+		{"priority", func(r *TaskRequest) { r.Priority = "low" }, false},
+		{"estimate", func(r *TaskRequest) { r.Estimate = "" }, false},
+		{"due date", func(r *TaskRequest) { r.DueDate = "" }, false},
+		{"assignee", func(r *TaskRequest) { r.AssigneeID = &other }, false},
+	}
+	for _, test := range tests {
+		req := unchanged
+		test.edit(&req)
+		if got := onlyStatusChanged(task, req); got != test.want {
+			t.Errorf("%s changed: onlyStatusChanged = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
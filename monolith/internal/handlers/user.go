@@ -81,3 +81,67 @@ func UpdateCurrentUser(c *gin.Context) {
 		"user":    user,
 	})
 }
+
+// @Summary		Get notification preferences
+// @Description	Get which notifications the current user receives, on which channels, and when
+// @Tags			users
+// @Produce		json
+// @Security		BearerAuth
+// @Success		200	{object}	map[string]interface{}
+// @Failure		401	{object}	map[string]interface{}
+// @Router			/users/me/notifications [get]
+func GetNotificationPreferences(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	var user models.User
+	if err := database.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"preferences": user.NotificationPreferences,
+		"event_types": models.NotificationEventTypes,
+		"channels":    models.NotificationChannels,
+	})
+}
+
+// @Summary		Update notification preferences
+// @Description	Replace the current user's notification preferences. Omitted fields get their defaults: every event by email right away, no quiet hours, and the daily digest on.
+// @Tags			users
+// @Accept			json
+// @Produce		json
+// @Security		BearerAuth
+// @Param			preferences	body		models.NotificationPreferences	true	"Notification preferences"
+// @Success		200			{object}	map[string]interface{}
+// @Failure		400			{object}	map[string]interface{}
+// @Failure		401			{object}	map[string]interface{}
+// @Router			/users/me/notifications [put]
+func UpdateNotificationPreferences(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	preferences := models.DefaultNotificationPreferences()
+	if err := c.ShouldBindJSON(&preferences); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if preferences.Events == nil {
+		preferences.Events = map[string]models.EventPreference{}
+	}
+	if err := preferences.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result := database.DB.Model(&models.User{}).Where("id = ?", userID).Update("notification_preferences", preferences)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification preferences"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "Notification preferences updated successfully",
+		"preferences": preferences,
+	})
+}
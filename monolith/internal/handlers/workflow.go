@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/P4rz1val22/task-management-api/internal/database"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	"github.com/P4rz1val22/task-management-api/internal/outbox"
+	"github.com/P4rz1val22/task-management-api/internal/services"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+)
+
+// errValuesInUse is returned when tasks still use values a new workflow removes
+var errValuesInUse = errors.New("workflow values in use")
+
+// valuesInUse lists the values of a task column in a project that a new
+// workflow list no longer contains
+func valuesInUse(tx *gorm.DB, projectID uint, column string, allowed []string) ([]string, error) {
+	query := tx.Model(&models.Task{}).Where("project_id = ? AND "+column+" <> ''", projectID)
+	if len(allowed) > 0 {
+		query = query.Where(column+" NOT IN ?", allowed)
+	}
+
+	var values []string
+	err := query.Distinct(column).Pluck(column, &values).Error
+	return values, err
+}
+
+// @Summary    Get project workflow
+// @Description Get the statuses, allowed transitions, priorities and estimates of a project owned by the authenticated user
+// @Tags       projects
+// @Produce    json
+// @Security   BearerAuth
+// @Param      id    path    int    true    "Project ID"
+// @Success    200   {object}    map[string]interface{}
+// @Failure    401   {object}    map[string]interface{}
+// @Failure    404   {object}    map[string]interface{}
+// @Router     /projects/{id}/workflow [get]
+func GetProjectWorkflow(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var project models.Project
+	if err := database.DB.Where("id = ? AND owner_id = ?", c.Param("id"), userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project_id": project.ID,
+		"workflow":   project.Workflow,
+	})
+}
+
+// @Summary    Update project workflow
+// @Description Replace the workflow of a project owned by the authenticated user. Statuses, priorities and estimates still used by its tasks cannot be removed.
+// @Tags       projects
+// @Accept     json
+// @Produce    json
+// @Security   BearerAuth
+// @Param      id        path    int              true   "Project ID"
+// @Param      workflow  body    models.Workflow  true   "Workflow definition"
+// @Success    200       {object} map[string]interface{}
+// @Failure    400       {object} map[string]interface{}
+// @Failure    401       {object} map[string]interface{}
+// @Failure    404       {object} map[string]interface{}
+// @Failure    409       {object} map[string]interface{}
+// @Router     /projects/{id}/workflow [put]
+func UpdateProjectWorkflow(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var workflow models.Workflow
+	if err := c.ShouldBindJSON(&workflow); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := workflow.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var project models.Project
+	if err := database.DB.Where("id = ? AND owner_id = ?", c.Param("id"), userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	inUse := gin.H{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Task writes lock the project row too (FOR SHARE) and check their
+		// values against its workflow, so with the row locked no task can
+		// take up a removed value before this commits
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&project, project.ID).Error; err != nil {
+			return err
+		}
+		for column, allowed := range map[string][]string{"status": workflow.Statuses, "priority": workflow.Priorities, "estimate": workflow.Estimates} {
+			values, err := valuesInUse(tx, project.ID, column, allowed)
+			if err != nil {
+				return err
+			}
+			if len(values) > 0 {
+				inUse[column] = values
+			}
+		}
+		if len(inUse) > 0 {
+			return errValuesInUse
+		}
+
+		if err := tx.Model(&project).Update("workflow", workflow).Error; err != nil {
+			return err
+		}
+		project.Workflow = workflow
+		return outbox.Enqueue(tx, outbox.ProjectWorkflowUpdated, project.ID, userID, services.NewProjectEvent(project, nil))
+	})
+	if errors.Is(err, errValuesInUse) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Tasks still use values this workflow removes. Move them first.",
+			"in_use": inUse,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workflow"})
+		return
+	}
+	outbox.Wake()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Workflow updated successfully",
+		"project_id": project.ID,
+		"workflow":   workflow,
+	})
+}
@@ -0,0 +1,49 @@
+package mail
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileMailer writes each email to a maildir instead of sending it, for
+// local development. Any maildir-aware client (e.g. mutt -f mail/) can
+// read them, and each file is a plain .eml.
+type FileMailer struct {
+	Dir string
+}
+
+func NewFileMailer(dir string) *FileMailer {
+	return &FileMailer{Dir: dir}
+}
+
+// Send writes the message to tmp/ and then moves it to new/, so readers
+// never see a partly written email
+func (m *FileMailer) Send(msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range []string{"tmp", "new", "cur"} {
+		if err := os.MkdirAll(filepath.Join(m.Dir, sub), 0o755); err != nil {
+			return err
+		}
+	}
+
+	hostname, _ := os.Hostname()
+	name := fmt.Sprintf("%d.%s.%s.eml", time.Now().UnixNano(), randomHex(4), hostname)
+	tmpPath := filepath.Join(m.Dir, "tmp", name)
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	newPath := filepath.Join(m.Dir, "new", name)
+	if err := os.Rename(tmpPath, newPath); err != nil {
+		return err
+	}
+
+	log.Printf("📧 EMAIL WRITTEN: %s to %s (%s)", msg.Subject, msg.To, newPath)
+	return nil
+}
@@ -0,0 +1,139 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+)
+
+// Message is an email with an HTML body and its plain-text alternative
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// Transports MAIL_TRANSPORT can select
+const (
+	TransportSMTP   = "smtp"
+	TransportFile   = "file"
+	TransportMemory = "memory"
+)
+
+// FromEnv builds the mailer selected by MAIL_TRANSPORT. When it is unset,
+// SMTP is used if SMTP_HOST or SMTP_USERNAME is set, and the file sink
+// otherwise, so local setups keep their emails without any configuration.
+func FromEnv() (Mailer, error) {
+	transport := os.Getenv("MAIL_TRANSPORT")
+	if transport == "" {
+		transport = TransportFile
+		if os.Getenv("SMTP_HOST") != "" || os.Getenv("SMTP_USERNAME") != "" {
+			transport = TransportSMTP
+		}
+	}
+
+	switch transport {
+	case TransportSMTP:
+		mailer, err := SMTPFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("📧 Email transport: SMTP via %s:%s (%s)", mailer.Host, mailer.Port, mailer.Security)
+		return mailer, nil
+	case TransportFile:
+		dir := os.Getenv("MAIL_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		log.Printf("📧 Email transport: writing emails to the maildir %s", dir)
+		return NewFileMailer(dir), nil
+	case TransportMemory:
+		log.Printf("📧 Email transport: keeping emails in memory")
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("invalid MAIL_TRANSPORT %q, use: smtp, file, memory", transport)
+	}
+}
+
+// Bytes renders the message as a MIME email: a multipart/alternative body
+// with the plain-text part first, so clients prefer the HTML one
+func (m Message) Bytes() ([]byte, error) {
+	for _, address := range []string{m.From, m.To} {
+		if strings.ContainsAny(address, "\r\n") {
+			return nil, errors.New("mail: line break in address")
+		}
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	for _, header := range [][2]string{
+		{"From", m.From},
+		{"To", m.To},
+		{"Subject", mime.QEncoding.Encode("UTF-8", m.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID(m.From)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `multipart/alternative; boundary="` + parts.Boundary() + `"`},
+	} {
+		fmt.Fprintf(&msg, "%s: %s\r\n", header[0], header[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// messageID makes a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "<> ")
+	}
+	return fmt.Sprintf("<%d.%s@%s>", time.Now().UnixNano(), randomHex(8), domain)
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
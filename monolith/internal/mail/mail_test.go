@@ -0,0 +1,83 @@
+package mail
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+)
+
+func TestBytesRejectsLineBreaksInAddresses(t *testing.T) {
+	tests := []Message{
+		{From: "tasks@example.com", To: "ada@example.com\r\nBcc: everyone@example.com"},
+		{From: "tasks@example.com", To: "ada@example.com\nBcc: everyone@example.com"},
+		{From: "tasks@example.com\r\nReply-To: attacker@example.com", To: "ada@example.com"},
+		{From: "tasks@example.com", To: "ada@example.com\r"},
+	}
+	for _, msg := range tests {
+		if _, err := msg.Bytes(); err == nil {
+			t.Errorf("Bytes accepted From %q, To %q", msg.From, msg.To)
+		}
+	}
+}
+
+func TestMemoryMailerRejectsLineBreaksInAddresses(t *testing.T) {
+	mailer := NewMemoryMailer()
+	if err := mailer.Send(Message{From: "tasks@example.com", To: "ada@example.com\r\nBcc: everyone@example.com"}); err == nil {
+		t.Error("Send accepted a line break in the recipient")
+	}
+	if len(mailer.Messages()) != 0 {
+		t.Error("the rejected message was kept")
+	}
+}
+
+func TestBytes(t *testing.T) {
+	msg := Message{
+		From:    "Tasks <tasks@example.com>",
+		To:      "ada@example.com",
+		Subject: "✅ New Task Created: Ship it",
+		Text:    "Ship it",
+		HTML:    "<p>Ship it</p>",
+	}
+	raw, err := msg.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes: %v", err)
+	}
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("the message doesn't parse: %v", err)
+	}
+	if got := parsed.Header.Get("To"); got != msg.To {
+		t.Errorf("To = %q, want %q", got, msg.To)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != msg.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, msg.Subject)
+	}
+	if !strings.HasSuffix(parsed.Header.Get("Message-ID"), "@example.com>") {
+		t.Errorf("Message-ID %q is not in the sender's domain", parsed.Header.Get("Message-ID"))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q (%v)", parsed.Header.Get("Content-Type"), err)
+	}
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		part, err := parts.NextPart()
+		if err != nil {
+			t.Fatalf("missing %s part: %v", want.contentType, err)
+		}
+		body, _ := io.ReadAll(part)
+		if part.Header.Get("Content-Type") != want.contentType || string(body) != want.body {
+			t.Errorf("part %q = %q, want %q: %q", part.Header.Get("Content-Type"), body, want.contentType, want.body)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package mail
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"os"
+	"time"
+)
+
+// SMTP connection security
+const (
+	// SecurityStartTLS upgrades a plain connection with STARTTLS, and fails
+	// if the server doesn't offer it
+	SecurityStartTLS = "starttls"
+	// SecurityTLS connects over TLS from the start (SMTPS, usually port 465)
+	SecurityTLS = "tls"
+	// SecurityNone sends in the clear; only for local relays such as MailHog
+	SecurityNone = "none"
+)
+
+// SMTPMailer sends through an SMTP server, authenticating with PLAIN when
+// a username is set. Credentials are never sent over an unencrypted
+// connection to a remote host.
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	Security string
+	// Timeout bounds connecting and the whole exchange
+	Timeout time.Duration
+}
+
+// SMTPFromEnv configures an SMTP mailer from SMTP_HOST (default
+// smtp.gmail.com), SMTP_SECURITY (starttls, tls or none; default starttls),
+// SMTP_PORT (default 465 with tls, 587 otherwise), SMTP_USERNAME and
+// SMTP_PASSWORD
+func SMTPFromEnv() (*SMTPMailer, error) {
+	mailer := &SMTPMailer{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     os.Getenv("SMTP_PORT"),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		Security: os.Getenv("SMTP_SECURITY"),
+		Timeout:  30 * time.Second,
+	}
+	if mailer.Host == "" {
+		mailer.Host = "smtp.gmail.com"
+	}
+	if mailer.Security == "" {
+		mailer.Security = SecurityStartTLS
+	}
+	if mailer.Security != SecurityStartTLS && mailer.Security != SecurityTLS && mailer.Security != SecurityNone {
+		return nil, fmt.Errorf("invalid SMTP_SECURITY %q, use: starttls, tls, none", mailer.Security)
+	}
+	if mailer.Port == "" {
+		mailer.Port = "587"
+		if mailer.Security == SecurityTLS {
+			mailer.Port = "465"
+		}
+	}
+	return mailer, nil
+}
+
+// Send delivers the message over a new connection
+func (m *SMTPMailer) Send(msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(m.Host, m.Port)
+	dialer := &net.Dialer{Timeout: m.Timeout}
+	tlsConfig := &tls.Config{ServerName: m.Host}
+
+	var conn net.Conn
+	if m.Security == SecurityTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(m.Timeout))
+
+	client, err := smtp.NewClient(conn, m.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if m.Security == SecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp: server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, m.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(envelopeAddress(msg.From)); err != nil {
+		return err
+	}
+	if err := client.Rcpt(envelopeAddress(msg.To)); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// envelopeAddress extracts the bare address from one like "Name <address>"
+func envelopeAddress(address string) string {
+	if parsed, err := netmail.ParseAddress(address); err == nil {
+		return parsed.Address
+	}
+	return address
+}
@@ -0,0 +1,262 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Notification event types users can configure
+const (
+	NotifyTaskCreated = "task.created"
+	NotifyTaskUpdated = "task.updated"
+	NotifyTaskDueSoon = "task.due_soon"
+	NotifyTaskOverdue = "task.overdue"
+)
+
+// NotificationEventTypes lists every configurable event type
+var NotificationEventTypes = []string{NotifyTaskCreated, NotifyTaskUpdated, NotifyTaskDueSoon, NotifyTaskOverdue}
+
+// Notification channels; email is the only one so far
+const ChannelEmail = "email"
+
+// NotificationChannels lists every channel
+var NotificationChannels = []string{ChannelEmail}
+
+// How an event's notifications are delivered
+const (
+	DeliveryImmediate = "immediate"
+	DeliveryDigest    = "digest"
+)
+
+// What to do with a notification, as decided by NotificationPreferences.Plan
+const (
+	// NotifySkip drops the notification
+	NotifySkip = "skip"
+	// NotifyNow sends it right away
+	NotifyNow = "now"
+	// NotifyDigest holds it for the next daily digest
+	NotifyDigest = "digest"
+	// NotifyLater holds it until quiet hours end
+	NotifyLater = "later"
+)
+
+// NotificationPreferences is how a user wants to be notified. Event types
+// without settings are sent by email right away, and project settings
+// override the user's own for tasks in that project.
+type NotificationPreferences struct {
+	// Events maps an event type to how it is delivered
+	Events map[string]EventPreference `json:"events"`
+	// Projects maps a project ID to the settings for its tasks
+	Projects map[uint]ProjectNotificationPreference `json:"projects,omitempty"`
+	// QuietHours holds back immediate notifications; nil means none
+	QuietHours *QuietHours `json:"quiet_hours"`
+	// DailyDigest is whether the daily digest is sent
+	DailyDigest bool `json:"daily_digest"`
+}
+
+// EventPreference is how one event type is delivered
+type EventPreference struct {
+	// Channels the event is sent on; none turns it off
+	Channels []string `json:"channels"`
+	// Delivery is immediate (the default), or digest to list the event in
+	// the daily digest instead
+	Delivery string `json:"delivery,omitempty"`
+}
+
+// ProjectNotificationPreference is how a user is notified about one project
+type ProjectNotificationPreference struct {
+	// Muted turns off every notification about the project
+	Muted bool `json:"muted"`
+	// Events override the user's event settings within the project
+	Events map[string]EventPreference `json:"events,omitempty"`
+}
+
+// QuietHours is a daily window, such as 22:00 to 07:00, during which
+// notifications are held and sent when it ends
+type QuietHours struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// TimeZone is an IANA time zone name; empty means UTC
+	TimeZone string `json:"time_zone"`
+}
+
+// DefaultNotificationPreferences are the preferences users start with
+func DefaultNotificationPreferences() NotificationPreferences {
+	return NotificationPreferences{Events: map[string]EventPreference{}, DailyDigest: true}
+}
+
+// defaultEventPreference applies to event types without settings
+var defaultEventPreference = EventPreference{Channels: []string{ChannelEmail}, Delivery: DeliveryImmediate}
+
+// Value stores the preferences as JSON; zero preferences, as on new users,
+// are stored as the defaults
+func (p NotificationPreferences) Value() (driver.Value, error) {
+	if p.Events == nil {
+		p = DefaultNotificationPreferences()
+	}
+	data, err := json.Marshal(p)
+	return string(data), err
+}
+
+// Scan reads stored preferences; users without any get the defaults
+func (p *NotificationPreferences) Scan(value interface{}) error {
+	*p = DefaultNotificationPreferences()
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return errors.New("unsupported type for NotificationPreferences")
+	}
+}
+
+// Validate checks every setting names a known event type, channel and
+// delivery, and that the quiet hours can be read
+func (p NotificationPreferences) Validate() error {
+	if err := validateEventPreferences(p.Events, p.DailyDigest); err != nil {
+		return err
+	}
+	for projectID, project := range p.Projects {
+		if err := validateEventPreferences(project.Events, p.DailyDigest); err != nil {
+			return fmt.Errorf("project %d: %w", projectID, err)
+		}
+	}
+	if p.QuietHours != nil {
+		return p.QuietHours.Validate()
+	}
+	return nil
+}
+
+func validateEventPreferences(events map[string]EventPreference, dailyDigest bool) error {
+	for eventType, event := range events {
+		if !contains(NotificationEventTypes, eventType) {
+			return fmt.Errorf("unknown event type %q", eventType)
+		}
+		for _, channel := range event.Channels {
+			if !contains(NotificationChannels, channel) {
+				return fmt.Errorf("unknown channel %q for %s", channel, eventType)
+			}
+		}
+		switch event.Delivery {
+		case "", DeliveryImmediate:
+		case DeliveryDigest:
+			if !dailyDigest {
+				return fmt.Errorf("%s is delivered in the digest, which is turned off", eventType)
+			}
+		default:
+			return fmt.Errorf("invalid delivery %q for %s", event.Delivery, eventType)
+		}
+	}
+	return nil
+}
+
+// Event is how an event type is delivered for a task in the given project
+func (p NotificationPreferences) Event(eventType string, projectID uint) EventPreference {
+	if project, ok := p.Projects[projectID]; ok {
+		if project.Muted {
+			return EventPreference{}
+		}
+		if event, ok := project.Events[eventType]; ok {
+			return event
+		}
+	}
+	if event, ok := p.Events[eventType]; ok {
+		return event
+	}
+	return defaultEventPreference
+}
+
+// Plan decides what to do with a notification sent on a channel at the given
+// time. When it is NotifyLater, the time returned is when quiet hours end.
+func (p NotificationPreferences) Plan(eventType, channel string, projectID uint, now time.Time) (string, time.Time) {
+	event := p.Event(eventType, projectID)
+	if !contains(event.Channels, channel) {
+		return NotifySkip, time.Time{}
+	}
+	if event.Delivery == DeliveryDigest {
+		return NotifyDigest, time.Time{}
+	}
+	if until, quiet := p.QuietHours.Until(now); quiet {
+		return NotifyLater, until
+	}
+	return NotifyNow, time.Time{}
+}
+
+// Validate checks the window is two different HH:MM times in a known zone
+func (q QuietHours) Validate() error {
+	start, err := time.Parse("15:04", q.Start)
+	if err != nil {
+		return fmt.Errorf("quiet hours start %q is not a HH:MM time", q.Start)
+	}
+	end, err := time.Parse("15:04", q.End)
+	if err != nil {
+		return fmt.Errorf("quiet hours end %q is not a HH:MM time", q.End)
+	}
+	if start.Equal(end) {
+		return errors.New("quiet hours cannot start and end at the same time")
+	}
+	if _, err := time.LoadLocation(q.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone %q", q.TimeZone)
+	}
+	return nil
+}
+
+// Until reports whether the time falls within the quiet hours and, if so,
+// when they end. Windows may wrap past midnight.
+func (q *QuietHours) Until(now time.Time) (time.Time, bool) {
+	if q == nil {
+		return time.Time{}, false
+	}
+	start, err1 := time.Parse("15:04", q.Start)
+	end, err2 := time.Parse("15:04", q.End)
+	location, err3 := time.LoadLocation(q.TimeZone)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return time.Time{}, false
+	}
+
+	local := now.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	var quiet bool
+	if startMinute < endMinute {
+		quiet = minute >= startMinute && minute < endMinute
+	} else {
+		quiet = minute >= startMinute || minute < endMinute
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	until := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, location)
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+// PendingNotification is a notification to a user about an outbox event.
+// Most are sent right away and only record that they were; the others are
+// held for the user's next daily digest, or until their quiet hours end.
+// Notifications are keyed by the event they came from, so an event that is
+// delivered again is neither sent nor held twice.
+type PendingNotification struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	UserID    uint         `json:"user_id" gorm:"not null;uniqueIndex:idx_pending_notification"`
+	EventID   uint         `json:"event_id" gorm:"not null;uniqueIndex:idx_pending_notification"`
+	EventType string       `json:"event_type" gorm:"not null"`
+	Payload   EventPayload `json:"payload" gorm:"type:jsonb;not null"`
+	// Digest is true for notifications held for the digest; the others are
+	// sent on their own once SendAt has passed
+	Digest    bool       `json:"digest" gorm:"not null;default:false"`
+	SendAt    time.Time  `json:"send_at" gorm:"not null;index"`
+	SentAt    *time.Time `json:"sent_at" gorm:"index"`
+	CreatedAt time.Time  `json:"created_at"`
+}
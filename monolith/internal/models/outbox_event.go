@@ -0,0 +1,60 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"time"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the change
+// it describes, so the event exists exactly when the change does. The outbox
+// relay publishes it afterwards and stamps PublishedAt.
+type OutboxEvent struct {
+	ID            uint         `json:"id" gorm:"primaryKey"`
+	Type          string       `json:"type" gorm:"not null;index"`
+	AggregateType string       `json:"aggregate_type" gorm:"not null;index:idx_outbox_aggregate"`
+	AggregateID   uint         `json:"aggregate_id" gorm:"not null;index:idx_outbox_aggregate"`
+	ActorID       uint         `json:"actor_id"`
+	Payload       EventPayload `json:"payload" gorm:"type:jsonb;not null"`
+	Attempts      int          `json:"attempts" gorm:"not null;default:0"`
+	LastError     string       `json:"last_error"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" gorm:"not null;index"`
+	PublishedAt   *time.Time   `json:"published_at" gorm:"index"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// EventPayload is raw JSON stored as jsonb
+type EventPayload []byte
+
+func (p EventPayload) Value() (driver.Value, error) {
+	if len(p) == 0 {
+		return "null", nil
+	}
+	return string(p), nil
+}
+
+func (p *EventPayload) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*p = nil
+	case []byte:
+		*p = append(EventPayload(nil), v...)
+	case string:
+		*p = EventPayload(v)
+	default:
+		return errors.New("unsupported event payload type")
+	}
+	return nil
+}
+
+func (p EventPayload) MarshalJSON() ([]byte, error) {
+	if len(p) == 0 {
+		return []byte("null"), nil
+	}
+	return p, nil
+}
+
+func (p *EventPayload) UnmarshalJSON(data []byte) error {
+	*p = append(EventPayload(nil), data...)
+	return nil
+}
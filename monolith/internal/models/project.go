@@ -11,6 +11,7 @@ type Project struct {
 	Description string         `json:"description"`
 	OwnerID     uint           `json:"owner_id" gorm:"not null"`
 	Owner       *User          `json:"owner" gorm:"foreignKey:OwnerID"`
+	Workflow    Workflow       `json:"workflow" gorm:"type:jsonb"`
 	Tasks       []Task         `json:"tasks,omitempty" gorm:"foreignKey:ProjectID"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
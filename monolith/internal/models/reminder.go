@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Reminder kinds
+const (
+	ReminderDueSoon = "due_soon"
+	ReminderOverdue = "overdue"
+	ReminderDigest  = "digest"
+)
+
+// Reminder records a reminder that was sent, so the scheduler never sends
+// the same one twice. Task reminders are keyed by the due date they were
+// sent for, so moving a task's due date allows new ones; digests have no
+// task and are keyed by the day they cover.
+type Reminder struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Kind      string    `json:"kind" gorm:"not null;uniqueIndex:idx_reminder"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_reminder"`
+	TaskID    uint      `json:"task_id" gorm:"not null;default:0;uniqueIndex:idx_reminder"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_reminder"`
+	CreatedAt time.Time `json:"created_at"`
+}
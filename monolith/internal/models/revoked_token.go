@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RevokedToken is the shared denylist of access token IDs (jti claims),
+// written by auth-service on logout
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Task event actions
+const (
+	TaskCreated = "created"
+	TaskUpdated = "updated"
+	TaskDeleted = "deleted"
+)
+
+// TaskEvent is one entry in a task's audit trail: who did what, when, and
+// the before and after value of every field that changed. ProjectID is the
+// project the task was in at the time.
+type TaskEvent struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	TaskID    uint         `json:"task_id" gorm:"not null;index"`
+	ProjectID uint         `json:"project_id" gorm:"index"`
+	ActorID   uint         `json:"actor_id" gorm:"not null"`
+	Actor     *User        `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	Action    string       `json:"action" gorm:"not null"`
+	Changes   FieldChanges `json:"changes" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// FieldChange is a field's value before and after an event. Values are
+// rendered as strings; empty means unset.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// FieldChanges is stored as a JSON array
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *FieldChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return errors.New("unsupported type for FieldChanges")
+	}
+}
+
+// auditValues renders the audited fields of a task, in display order
+func (t Task) auditValues() [][2]string {
+	values := [][2]string{
+		{"title", t.Title},
+		{"description", t.Description},
+		{"project_id", formatID(&t.ProjectID)},
+		{"assignee_id", formatID(t.AssigneeID)},
+		{"status", t.Status},
+		{"priority", t.Priority},
+		{"estimate", t.Estimate},
+		{"due_date", ""},
+	}
+	if t.DueDate != nil {
+		values[len(values)-1][1] = t.DueDate.Format("2006-01-02")
+	}
+	return values
+}
+
+func formatID(id *uint) string {
+	if id == nil || *id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// TaskChanges lists the audited fields that differ between two versions of a
+// task. Diff against Task{} to snapshot a created or deleted task.
+func TaskChanges(before, after Task) FieldChanges {
+	from, to := before.auditValues(), after.auditValues()
+
+	var changes FieldChanges
+	for i := range from {
+		if from[i][1] != to[i][1] {
+			changes = append(changes, FieldChange{Field: from[i][0], From: from[i][1], To: to[i][1]})
+		}
+	}
+	return changes
+}
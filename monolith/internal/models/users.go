@@ -14,4 +14,7 @@ type User struct {
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	// NotificationPreferences are served at /users/me/notifications
+	NotificationPreferences NotificationPreferences `json:"-" gorm:"type:jsonb"`
 }
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Workflow is a project's task lifecycle: the statuses a task moves through,
+// which moves are allowed, and the priority and estimate scales. Lists are
+// ordered; sorting by status, priority or estimate follows that order.
+type Workflow struct {
+	// Statuses lists every status; new tasks start in the first one
+	Statuses []string `json:"statuses"`
+	// DoneStatus is the status that counts as finished
+	DoneStatus string `json:"done_status"`
+	// Transitions maps a status to the statuses it may move to. A status
+	// without an entry may move to any status.
+	Transitions map[string][]string `json:"transitions,omitempty"`
+	Priorities  []string            `json:"priorities"`
+	Estimates   []string            `json:"estimates"`
+}
+
+// DefaultWorkflow is the workflow new projects start with
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses:   []string{"Not Started", "In Progress", "Blocked", "Done"},
+		DoneStatus: "Done",
+		Priorities: []string{"Low", "Medium", "High", "Urgent"},
+		Estimates:  []string{"S", "M", "L", "XL"},
+	}
+}
+
+// Value stores the workflow as JSON; an empty workflow is stored as the default
+func (w Workflow) Value() (driver.Value, error) {
+	if len(w.Statuses) == 0 {
+		w = DefaultWorkflow()
+	}
+	data, err := json.Marshal(w)
+	return string(data), err
+}
+
+// Scan reads a stored workflow; projects without one get the default
+func (w *Workflow) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = DefaultWorkflow()
+		return nil
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	default:
+		return errors.New("unsupported type for Workflow")
+	}
+}
+
+// Validate checks the workflow is self-consistent
+func (w Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return errors.New("workflow needs at least one status")
+	}
+	for name, values := range map[string][]string{"status": w.Statuses, "priority": w.Priorities, "estimate": w.Estimates} {
+		seen := map[string]bool{}
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("%s names cannot be empty", name)
+			}
+			if seen[value] {
+				return fmt.Errorf("duplicate %s %q", name, value)
+			}
+			seen[value] = true
+		}
+	}
+	if !w.HasStatus(w.DoneStatus) {
+		return fmt.Errorf("done_status %q is not one of the statuses", w.DoneStatus)
+	}
+	for from, targets := range w.Transitions {
+		if !w.HasStatus(from) {
+			return fmt.Errorf("transition from unknown status %q", from)
+		}
+		for _, to := range targets {
+			if !w.HasStatus(to) {
+				return fmt.Errorf("transition from %q to unknown status %q", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+func (w Workflow) InitialStatus() string {
+	return w.Statuses[0]
+}
+
+func (w Workflow) HasStatus(status string) bool {
+	return contains(w.Statuses, status)
+}
+
+func (w Workflow) HasPriority(priority string) bool {
+	return contains(w.Priorities, priority)
+}
+
+func (w Workflow) HasEstimate(estimate string) bool {
+	return contains(w.Estimates, estimate)
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Staying in the same status is always allowed.
+func (w Workflow) CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	targets, restricted := w.Transitions[from]
+	return !restricted || contains(targets, to)
+}
+
+// NextStatuses lists the statuses a task may move to from the given one
+func (w Workflow) NextStatuses(from string) []string {
+	var next []string
+	for _, status := range w.Statuses {
+		if status != from && w.CanTransition(from, status) {
+			next = append(next, status)
+		}
+	}
+	return next
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
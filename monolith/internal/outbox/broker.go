@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"errors"
+	"sync"
+)
+
+// Broker delivers published events to their consumers. The relay delivers
+// every event at least once: a Publish that fails, or whose outcome is lost,
+// is retried, so consumers must tolerate duplicates.
+type Broker interface {
+	Publish(event Event) error
+}
+
+// Handler consumes an event. Returning an error makes the relay retry it.
+type Handler func(event Event) error
+
+// MemoryBroker is an in-process broker: Publish runs the subscribed handlers
+// synchronously. It also keeps every event it accepted, for tests.
+type MemoryBroker struct {
+	mu          sync.Mutex
+	subscribers map[string][]Handler
+	published   []Event
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subscribers: map[string][]Handler{}}
+}
+
+// Subscribe registers a handler for the given event types, or for every
+// event when no types are given
+func (b *MemoryBroker) Subscribe(handler Handler, eventTypes ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(eventTypes) == 0 {
+		eventTypes = []string{"*"}
+	}
+	for _, eventType := range eventTypes {
+		b.subscribers[eventType] = append(b.subscribers[eventType], handler)
+	}
+}
+
+// Publish hands the event to its subscribers and fails if any of them does
+func (b *MemoryBroker) Publish(event Event) error {
+	b.mu.Lock()
+	handlers := append(append([]Handler(nil), b.subscribers[event.Type]...), b.subscribers["*"]...)
+	b.mu.Unlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.published = append(b.published, event)
+	b.mu.Unlock()
+	return nil
+}
+
+// Published returns the events accepted so far, oldest first
+func (b *MemoryBroker) Published() []Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Event(nil), b.published...)
+}
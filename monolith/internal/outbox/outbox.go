@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"encoding/json"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+// Domain event types. The part before the dot is the aggregate the event
+// belongs to; events of one aggregate are published in the order written.
+const (
+	TaskCreated            = "task.created"
+	TaskUpdated            = "task.updated"
+	TaskDeleted            = "task.deleted"
+	ProjectCreated         = "project.created"
+	ProjectUpdated         = "project.updated"
+	ProjectWorkflowUpdated = "project.workflow_updated"
+	ProjectDeleted         = "project.deleted"
+)
+
+// Event is a domain event as brokers receive it. ID is the outbox row ID, so
+// subscribers can recognize an event delivered more than once.
+type Event struct {
+	ID            uint            `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   uint            `json:"aggregate_id"`
+	ActorID       uint            `json:"actor_id"`
+	Payload       json.RawMessage `json:"payload"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+}
+
+// Decode unmarshals the event's payload into v
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Enqueue writes an event to the outbox. Call it with the transaction that
+// makes the change, so the event is only published if the change commits.
+func Enqueue(tx *gorm.DB, eventType string, aggregateID, actorID uint, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	aggregateType, _, _ := strings.Cut(eventType, ".")
+	return tx.Create(&models.OutboxEvent{
+		Type:          eventType,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		ActorID:       actorID,
+		Payload:       data,
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+func eventFromRow(row models.OutboxEvent) Event {
+	return Event{
+		ID:            row.ID,
+		Type:          row.Type,
+		AggregateType: row.AggregateType,
+		AggregateID:   row.AggregateID,
+		ActorID:       row.ActorID,
+		Payload:       json.RawMessage(row.Payload),
+		OccurredAt:    row.CreatedAt,
+	}
+}
@@ -0,0 +1,134 @@
+package outbox
+
+import (
+	"fmt"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"task-management-pkg/worker"
+	"time"
+)
+
+const (
+	// batchSize bounds how many events one run publishes
+	batchSize = 100
+	// retention is how long published events are kept before being purged
+	retention = 7 * 24 * time.Hour
+	// lease is how long a claimed event is left to the relay that claimed
+	// it; after that, a crashed relay's event is claimed again
+	lease = 5 * time.Minute
+)
+
+// backoff spaces out publish retries: 5s after the first failure, doubling
+// up to ten minutes
+var backoff = worker.Backoff{First: 5 * time.Second, Max: 10 * time.Minute}
+
+// loop runs the relay; writers wake it rather than waiting for the next tick
+var loop = worker.NewLoop()
+
+// Wake asks the relay to publish pending events now. Call it after the
+// transaction that enqueued them commits.
+func Wake() {
+	loop.Wake()
+}
+
+// pendingInOrder selects unpublished events that have no earlier unpublished
+// event of the same aggregate, so a failing event holds back the events after it
+const pendingInOrder = "NOT EXISTS (SELECT 1 FROM outbox_events earlier WHERE earlier.aggregate_type = outbox_events.aggregate_type " +
+	"AND earlier.aggregate_id = outbox_events.aggregate_id AND earlier.published_at IS NULL AND earlier.id < outbox_events.id)"
+
+// Relay publishes outbox events to a broker, at least once and in order per
+// aggregate. Failed publishes are retried with exponential backoff for as
+// long as it takes.
+type Relay struct {
+	DB     *gorm.DB
+	Broker Broker
+}
+
+func NewRelay(db *gorm.DB, broker Broker) *Relay {
+	return &Relay{DB: db, Broker: broker}
+}
+
+// Start publishes pending events every interval, or sooner when woken, in
+// the background
+func (r *Relay) Start(interval time.Duration) {
+	loop.Start(interval, func() { r.Run(time.Now()) })
+}
+
+// Run publishes the events that are due and purges old published ones. It
+// returns how many events were published.
+func (r *Relay) Run(now time.Time) int {
+	var events []models.OutboxEvent
+	err := r.DB.Select("id", "aggregate_type", "aggregate_id").
+		Where("published_at IS NULL AND next_attempt_at <= ?", now).
+		Where(pendingInOrder).
+		Order("id").
+		Limit(batchSize).
+		Find(&events).Error
+	if err != nil {
+		log.Printf("Outbox relay: failed to find pending events: %v", err)
+		return 0
+	}
+
+	published := 0
+	blocked := map[string]bool{}
+	for _, event := range events {
+		aggregate := fmt.Sprintf("%s:%d", event.AggregateType, event.AggregateID)
+		if blocked[aggregate] {
+			continue
+		}
+
+		ok, err := r.publish(event.ID, now)
+		if err != nil {
+			log.Printf("Outbox relay: failed to process event %d: %v", event.ID, err)
+		}
+		if ok {
+			published++
+		} else {
+			blocked[aggregate] = true
+		}
+	}
+
+	err = r.DB.Where("published_at < ?", now.Add(-retention)).Delete(&models.OutboxEvent{}).Error
+	if err != nil {
+		log.Printf("Outbox relay: failed to purge published events: %v", err)
+	}
+	return published
+}
+
+// publish claims one event, sends it and records the outcome, reporting
+// whether it was published. The claim leases the event to this relay and
+// commits before sending, so no lock is held while the broker works and
+// concurrent relays leave the event alone. An outcome is only recorded
+// while the claim still holds.
+func (r *Relay) publish(eventID uint, now time.Time) (bool, error) {
+	var rows []models.OutboxEvent
+	result := r.DB.Model(&rows).Clauses(clause.Returning{}).
+		Where("id = ? AND published_at IS NULL AND next_attempt_at <= ?", eventID, now).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if len(rows) == 0 {
+		return false, nil
+	}
+	row := rows[0]
+	claimed := r.DB.Model(&models.OutboxEvent{}).Where("id = ? AND attempts = ? AND published_at IS NULL", row.ID, row.Attempts)
+
+	if err := r.Broker.Publish(eventFromRow(row)); err != nil {
+		log.Printf("Outbox relay: publishing %s event %d failed (attempt %d), retrying: %v", row.Type, row.ID, row.Attempts, err)
+		return false, claimed.Updates(map[string]interface{}{
+			"last_error":      err.Error(),
+			"next_attempt_at": now.Add(backoff.Delay(row.Attempts)),
+		}).Error
+	}
+
+	return true, claimed.Updates(map[string]interface{}{
+		"last_error":   "",
+		"published_at": time.Now(),
+	}).Error
+}
@@ -0,0 +1,221 @@
+package outbox
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testEvent is the type of the events these tests enqueue; its aggregate
+// type is "relaytest"
+const testEvent = "relaytest.changed"
+
+// testDB opens the database in TEST_DATABASE_URL, which must be one only
+// tests use: relays publish whatever is pending in it. Tests are skipped
+// without one.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if err := db.AutoMigrate(&models.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	clear := func() { db.Where("aggregate_type = ?", "relaytest").Delete(&models.OutboxEvent{}) }
+	clear()
+	t.Cleanup(clear)
+	return db
+}
+
+// enqueue writes one event per aggregate ID, in order, returning their IDs
+func enqueue(t *testing.T, db *gorm.DB, aggregateIDs ...uint) []uint {
+	t.Helper()
+	var ids []uint
+	for _, aggregateID := range aggregateIDs {
+		if err := Enqueue(db, testEvent, aggregateID, 1, map[string]uint{"aggregate": aggregateID}); err != nil {
+			t.Fatalf("enqueue: %v", err)
+		}
+		var row models.OutboxEvent
+		db.Where("aggregate_type = ?", "relaytest").Order("id DESC").First(&row)
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+// drain runs the relay until a run publishes nothing, as an aggregate's
+// later events wait for a run after its earlier ones were published
+func drain(relay *Relay, now time.Time) int {
+	total := 0
+	for {
+		published := relay.Run(now)
+		if published == 0 {
+			return total
+		}
+		total += published
+	}
+}
+
+func publishedIDs(broker *MemoryBroker) []uint {
+	var ids []uint
+	for _, event := range broker.Published() {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func TestRelayPublishesInOrderPerAggregate(t *testing.T) {
+	db := testDB(t)
+	ids := enqueue(t, db, 1, 2, 1, 2, 1)
+
+	broker := NewMemoryBroker()
+	relay := NewRelay(db, broker)
+	if published := drain(relay, time.Now()); published != len(ids) {
+		t.Fatalf("published %d events, want %d", published, len(ids))
+	}
+	if got := publishedIDs(broker); !slices.Equal(got, ids) {
+		t.Errorf("published %v, want %v", got, ids)
+	}
+
+	var unpublished int64
+	db.Model(&models.OutboxEvent{}).Where("aggregate_type = ? AND published_at IS NULL", "relaytest").Count(&unpublished)
+	if unpublished != 0 {
+		t.Errorf("%d events left unpublished", unpublished)
+	}
+}
+
+func TestRelayHoldsBackAggregateAfterFailure(t *testing.T) {
+	db := testDB(t)
+	ids := enqueue(t, db, 1, 2, 1, 2)
+
+	// The first event of aggregate 1 fails
+	broker := NewMemoryBroker()
+	broker.Subscribe(func(event Event) error {
+		if event.ID == ids[0] {
+			return errors.New("handler failed")
+		}
+		return nil
+	})
+	relay := NewRelay(db, broker)
+
+	drain(relay, time.Now())
+	if got, want := publishedIDs(broker), []uint{ids[1], ids[3]}; !slices.Equal(got, want) {
+		t.Errorf("published %v, want only aggregate 2's %v", got, want)
+	}
+}
+
+func TestRelayRetriesWithBackoff(t *testing.T) {
+	db := testDB(t)
+	ids := enqueue(t, db, 1, 1)
+
+	failures := 2
+	var mu sync.Mutex
+	broker := NewMemoryBroker()
+	broker.Subscribe(func(event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if event.ID == ids[0] && failures > 0 {
+			failures--
+			return errors.New("handler failed")
+		}
+		return nil
+	})
+	relay := NewRelay(db, broker)
+
+	start := time.Now()
+	if published := relay.Run(start); published != 0 {
+		t.Fatalf("Run published %d events after a failure, want 0", published)
+	}
+	var row models.OutboxEvent
+	db.First(&row, ids[0])
+	if row.Attempts != 1 || row.LastError != "handler failed" || row.PublishedAt != nil {
+		t.Fatalf("after one failure: attempts %d, last error %q, published %v", row.Attempts, row.LastError, row.PublishedAt)
+	}
+	if wait := row.NextAttemptAt.Sub(start); wait < backoff.First || wait > backoff.First+time.Minute {
+		t.Errorf("first retry in %v, want about %v", wait, backoff.First)
+	}
+
+	// Not due again until the backoff has passed
+	if published := relay.Run(start.Add(backoff.First / 2)); published != 0 {
+		t.Errorf("Run published %d events before the retry was due", published)
+	}
+
+	// The second failure doubles the wait
+	secondTry := row.NextAttemptAt
+	relay.Run(secondTry)
+	db.First(&row, ids[0])
+	if row.Attempts != 2 {
+		t.Fatalf("attempts = %d after the second failure, want 2", row.Attempts)
+	}
+	if wait := row.NextAttemptAt.Sub(secondTry); wait < 2*backoff.First {
+		t.Errorf("second retry in %v, want at least %v", wait, 2*backoff.First)
+	}
+
+	if published := drain(relay, row.NextAttemptAt); published != 2 {
+		t.Errorf("published %d events once the handler recovered, want 2", published)
+	}
+	if got := publishedIDs(broker); !slices.Equal(got, ids) {
+		t.Errorf("published %v, want %v", got, ids)
+	}
+}
+
+func TestConcurrentRelaysPublishOnce(t *testing.T) {
+	db := testDB(t)
+	var aggregates []uint
+	for i := uint(1); i <= 20; i++ {
+		aggregates = append(aggregates, i%5+1)
+	}
+	ids := enqueue(t, db, aggregates...)
+
+	var mu sync.Mutex
+	deliveries := map[uint]int{}
+	broker := NewMemoryBroker()
+	broker.Subscribe(func(event Event) error {
+		// Slow enough for the relays to contend for the same events
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		deliveries[event.ID]++
+		mu.Unlock()
+		return nil
+	})
+
+	now := time.Now()
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			drain(NewRelay(db, broker), now)
+		}()
+	}
+	wg.Wait()
+
+	for _, id := range ids {
+		if deliveries[id] != 1 {
+			t.Errorf("event %d delivered %d times, want once", id, deliveries[id])
+		}
+	}
+
+	// Events of each aggregate still went out in order
+	seen := map[uint]uint{}
+	for _, event := range broker.Published() {
+		if event.ID < seen[event.AggregateID] {
+			t.Errorf("aggregate %d: event %d published after event %d", event.AggregateID, event.ID, seen[event.AggregateID])
+		}
+		seen[event.AggregateID] = event.ID
+	}
+}
@@ -0,0 +1,237 @@
+package reminders
+
+import (
+	"github.com/P4rz1val22/task-management-api/internal/database"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	"github.com/P4rz1val22/task-management-api/internal/services"
+	"gorm.io/gorm/clause"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// notDone matches tasks that aren't in their own project's done status
+const notDone = "tasks.status <> (SELECT projects.workflow->>'done_status' FROM projects WHERE projects.id = tasks.project_id)"
+
+// Scheduler emails due date reminders and a daily digest, as far as each
+// user's notification preferences allow, and sends the notifications held
+// until quiet hours ended. Every reminder is recorded in the reminders table
+// first, so each is sent at most once.
+type Scheduler struct {
+	Email *services.EmailService
+	// DaysBefore is how many days ahead a due date counts as approaching
+	DaysBefore int
+	// DigestHour is the UTC hour after which the daily digest goes out
+	DigestHour int
+}
+
+// NewScheduler configures a scheduler from REMINDER_DAYS_BEFORE (default 1)
+// and DIGEST_HOUR (default 8)
+func NewScheduler(email *services.EmailService) *Scheduler {
+	return &Scheduler{
+		Email:      email,
+		DaysBefore: envInt("REMINDER_DAYS_BEFORE", 1, 0, 30),
+		DigestHour: envInt("DIGEST_HOUR", 8, 0, 23),
+	}
+}
+
+func envInt(name string, fallback, lowest, highest int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value < lowest || value > highest {
+		return fallback
+	}
+	return value
+}
+
+// Start checks for reminders to send every interval, in the background
+func (s *Scheduler) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			s.run(time.Now().UTC())
+			<-ticker.C
+		}
+	}()
+}
+
+func (s *Scheduler) run(now time.Time) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	horizon := today.AddDate(0, 0, s.DaysBefore)
+
+	// Every unfinished task that is overdue or due within the horizon
+	var tasks []models.Task
+	err := database.DB.Preload("Assignee").Preload("Project.Owner").
+		Where("due_date IS NOT NULL AND due_date < ?", horizon.AddDate(0, 0, 1)).
+		Where(notDone).
+		Order("due_date, id").
+		Find(&tasks).Error
+	if err != nil {
+		log.Printf("⏰ Reminder scheduler: failed to load tasks: %v", err)
+		return
+	}
+
+	overdue := map[uint][]models.Task{}
+	dueSoon := map[uint][]models.Task{}
+	recipients := map[uint]*models.User{}
+
+	for _, task := range tasks {
+		recipient := recipientOf(task)
+		if recipient == nil {
+			continue
+		}
+
+		kind, eventType, list := models.ReminderDueSoon, models.NotifyTaskDueSoon, dueSoon
+		if task.DueDate.Before(today) {
+			kind, eventType, list = models.ReminderOverdue, models.NotifyTaskOverdue, overdue
+		}
+
+		// Tasks the user doesn't want to hear about are left out of the
+		// digest too. Reminders held for quiet hours are simply not recorded,
+		// so a run after they end sends them.
+		action, _ := recipient.NotificationPreferences.Plan(eventType, models.ChannelEmail, task.ProjectID, now)
+		if action == models.NotifySkip {
+			continue
+		}
+		recipients[recipient.ID] = recipient
+		list[recipient.ID] = append(list[recipient.ID], task)
+		if action != models.NotifyNow {
+			continue
+		}
+
+		if kind == models.ReminderOverdue {
+			s.remind(kind, recipient, task, func() error {
+				return s.Email.SendTaskOverdueReminder(task, recipient.Email)
+			})
+		} else {
+			s.remind(kind, recipient, task, func() error {
+				return s.Email.SendTaskDueSoonReminder(task, recipient.Email)
+			})
+		}
+	}
+
+	s.sendPending(now)
+
+	if now.Hour() < s.DigestHour {
+		return
+	}
+
+	// Notifications held for the digest, and the users they are for
+	var held []models.PendingNotification
+	if err := database.DB.Where("digest AND sent_at IS NULL").Order("id").Find(&held).Error; err != nil {
+		log.Printf("⏰ Reminder scheduler: failed to load held notifications: %v", err)
+		return
+	}
+	updates := map[uint][]models.PendingNotification{}
+	var missing []uint
+	for _, pending := range held {
+		if _, ok := recipients[pending.UserID]; !ok && len(updates[pending.UserID]) == 0 {
+			missing = append(missing, pending.UserID)
+		}
+		updates[pending.UserID] = append(updates[pending.UserID], pending)
+	}
+	if len(missing) > 0 {
+		var users []models.User
+		if err := database.DB.Where("id IN ?", missing).Find(&users).Error; err != nil {
+			log.Printf("⏰ Reminder scheduler: failed to load users: %v", err)
+			return
+		}
+		for i := range users {
+			recipients[users[i].ID] = &users[i]
+		}
+	}
+
+	for userID, user := range recipients {
+		preferences := user.NotificationPreferences
+		if !preferences.DailyDigest {
+			continue
+		}
+		// Digests due during quiet hours go out once they end
+		if _, quiet := preferences.QuietHours.Until(now); quiet {
+			continue
+		}
+
+		s.claimAndSend(models.Reminder{Kind: models.ReminderDigest, UserID: userID, Date: today}, func() error {
+			if err := s.Email.SendDailyDigest(user.Email, user.Name, overdue[userID], dueSoon[userID], updates[userID]); err != nil {
+				return err
+			}
+			markSent(updates[userID], now)
+			return nil
+		})
+	}
+}
+
+// sendPending sends the notifications held until quiet hours ended. Each is
+// marked sent before sending, so it goes out once; a failed send unmarks it
+// for the next run.
+func (s *Scheduler) sendPending(now time.Time) {
+	var pending []models.PendingNotification
+	err := database.DB.Where("NOT digest AND sent_at IS NULL AND send_at <= ?", now).Order("id").Find(&pending).Error
+	if err != nil {
+		log.Printf("⏰ Reminder scheduler: failed to load held notifications: %v", err)
+		return
+	}
+
+	for _, notification := range pending {
+		result := database.DB.Model(&notification).Where("sent_at IS NULL").Update("sent_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			continue
+		}
+
+		var user models.User
+		if err := database.DB.First(&user, notification.UserID).Error; err != nil {
+			// The user is gone; there's nobody left to tell
+			continue
+		}
+		if err := s.Email.SendPendingNotification(notification, user.Email); err != nil {
+			database.DB.Model(&notification).Update("sent_at", nil)
+		}
+	}
+}
+
+// markSent records that held notifications went out in a digest
+func markSent(notifications []models.PendingNotification, now time.Time) {
+	if len(notifications) == 0 {
+		return
+	}
+	var ids []uint
+	for _, notification := range notifications {
+		ids = append(ids, notification.ID)
+	}
+	if err := database.DB.Model(&models.PendingNotification{}).Where("id IN ?", ids).Update("sent_at", now).Error; err != nil {
+		log.Printf("⏰ Reminder scheduler: failed to mark digest notifications sent: %v", err)
+	}
+}
+
+// recipientOf is who gets a task's reminders: its assignee, or the project
+// owner when nobody is assigned
+func recipientOf(task models.Task) *models.User {
+	if task.Assignee != nil {
+		return task.Assignee
+	}
+	return task.Project.Owner
+}
+
+// remind sends a task reminder once per task, kind and due date
+func (s *Scheduler) remind(kind string, user *models.User, task models.Task, send func() error) {
+	s.claimAndSend(models.Reminder{Kind: kind, UserID: user.ID, TaskID: task.ID, Date: *task.DueDate}, send)
+}
+
+// claimAndSend records a reminder and sends it, unless it was recorded
+// already. If sending fails the record is removed so the next run retries.
+func (s *Scheduler) claimAndSend(reminder models.Reminder, send func() error) {
+	result := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&reminder)
+	if result.Error != nil {
+		log.Printf("⏰ Reminder scheduler: failed to record %s reminder: %v", reminder.Kind, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	if err := send(); err != nil {
+		database.DB.Delete(&reminder)
+	}
+}
@@ -0,0 +1,236 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/P4rz1val22/task-management-api/internal/database"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	"github.com/P4rz1val22/task-management-api/internal/outbox"
+	"gorm.io/gorm/clause"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// TaskSnapshot is a task as carried by task.* events
+type TaskSnapshot struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   uint       `json:"project_id"`
+	AssigneeID  *uint      `json:"assignee_id"`
+	CreatorID   *uint      `json:"creator_id"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	Estimate    string     `json:"estimate"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+// TaskEventPayload is the payload of task.* events: the task after the
+// change (before it, for task.deleted) and, for task.updated, what changed
+type TaskEventPayload struct {
+	Task    TaskSnapshot   `json:"task"`
+	Changes []ChangeDetail `json:"changes,omitempty"`
+}
+
+// ProjectSnapshot is a project as carried by project.* events
+type ProjectSnapshot struct {
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	OwnerID     uint            `json:"owner_id"`
+	Workflow    models.Workflow `json:"workflow"`
+}
+
+// ProjectEventPayload is the payload of project.* events
+type ProjectEventPayload struct {
+	Project ProjectSnapshot `json:"project"`
+	Changes []ChangeDetail  `json:"changes,omitempty"`
+}
+
+// NewTaskEvent builds a task event payload. changes may be nil.
+func NewTaskEvent(task models.Task, changes models.FieldChanges) TaskEventPayload {
+	payload := TaskEventPayload{Task: TaskSnapshot{
+		ID:          task.ID,
+		Title:       task.Title,
+		Description: task.Description,
+		ProjectID:   task.ProjectID,
+		AssigneeID:  task.AssigneeID,
+		CreatorID:   task.CreatorID,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		Estimate:    task.Estimate,
+		DueDate:     task.DueDate,
+	}}
+	for _, change := range changes {
+		payload.Changes = append(payload.Changes, ChangeDetail{Field: change.Field, From: change.From, To: change.To})
+	}
+	return payload
+}
+
+// NewProjectEvent builds a project event payload. changes may be nil.
+func NewProjectEvent(project models.Project, changes []ChangeDetail) ProjectEventPayload {
+	return ProjectEventPayload{
+		Project: ProjectSnapshot{
+			ID:          project.ID,
+			Name:        project.Name,
+			Description: project.Description,
+			OwnerID:     project.OwnerID,
+			Workflow:    project.Workflow,
+		},
+		Changes: changes,
+	}
+}
+
+// model turns the snapshot back into a task for the email templates
+func (s TaskSnapshot) model() models.Task {
+	return models.Task{
+		ID:          s.ID,
+		Title:       s.Title,
+		Description: s.Description,
+		ProjectID:   s.ProjectID,
+		AssigneeID:  s.AssigneeID,
+		CreatorID:   s.CreatorID,
+		Status:      s.Status,
+		Priority:    s.Priority,
+		Estimate:    s.Estimate,
+		DueDate:     s.DueDate,
+	}
+}
+
+// emailLabels are the fields update emails describe, with their labels
+var emailLabels = map[string]string{"title": "Title", "status": "Status", "priority": "Priority", "estimate": "Estimate"}
+
+// emailChanges picks the changes update emails describe, with their labels
+func emailChanges(changes []ChangeDetail) []ChangeDetail {
+	var details []ChangeDetail
+	for _, change := range changes {
+		if label, ok := emailLabels[change.Field]; ok {
+			details = append(details, ChangeDetail{Field: label, From: change.From, To: change.To})
+		}
+	}
+	return details
+}
+
+// HandleTaskEvent emails the people a task concerns when it is created or
+// updated: its assignee and creator, and the previous assignee when it was
+// reassigned, but never the user who made the change. Each recipient's
+// notification preferences decide whether the email goes out now, is held
+// for their digest or until their quiet hours end, or isn't sent. Updates
+// that changed none of the fields the email describes are skipped.
+//
+// Every recipient's notification is recorded, keyed by event, before it is
+// sent, so recipients already emailed are skipped when the event is
+// delivered again. Failed sends are returned, after trying the other
+// recipients, so the relay retries the event for them.
+func (e *EmailService) HandleTaskEvent(event outbox.Event) error {
+	var payload TaskEventPayload
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	details := emailChanges(payload.Changes)
+	if event.Type == outbox.TaskUpdated && len(details) == 0 {
+		return nil
+	}
+
+	recipientIDs := taskRecipients(payload, event.ActorID)
+	if len(recipientIDs) == 0 {
+		return nil
+	}
+	// Users who are gone are simply not found; there's nobody left to tell
+	var users []models.User
+	if err := database.DB.Where("id IN ?", recipientIDs).Find(&users).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var errs []error
+	for _, user := range users {
+		action, until := user.NotificationPreferences.Plan(event.Type, models.ChannelEmail, payload.Task.ProjectID, now)
+		if action == models.NotifySkip {
+			continue
+		}
+		if until.IsZero() {
+			until = now
+		}
+		notification := models.PendingNotification{
+			UserID:    user.ID,
+			EventID:   event.ID,
+			EventType: event.Type,
+			Payload:   models.EventPayload(event.Payload),
+			Digest:    action == models.NotifyDigest,
+			SendAt:    until,
+		}
+		if err := database.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&notification).Error; err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if action != models.NotifyNow {
+			continue
+		}
+
+		// Marked sent before sending, so it goes out once: a notification
+		// sent on an earlier delivery of the event, or held since, isn't
+		// claimed again. A failed send unmarks it.
+		claimed := database.DB.Model(&models.PendingNotification{}).
+			Where("user_id = ? AND event_id = ? AND NOT digest AND sent_at IS NULL AND send_at <= ?", user.ID, event.ID, now).
+			Update("sent_at", now)
+		if claimed.Error != nil {
+			errs = append(errs, claimed.Error)
+			continue
+		}
+		if claimed.RowsAffected == 0 {
+			continue
+		}
+		if err := e.sendTaskEvent(event.Type, payload.Task.model(), user.Email, details); err != nil {
+			database.DB.Model(&models.PendingNotification{}).Where("user_id = ? AND event_id = ?", user.ID, event.ID).Update("sent_at", nil)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// taskRecipients lists who hears about a task event, leaving out the actor
+func taskRecipients(payload TaskEventPayload, actorID uint) []uint {
+	var ids []uint
+	add := func(id *uint) {
+		if id != nil && *id != 0 && *id != actorID && !slices.Contains(ids, *id) {
+			ids = append(ids, *id)
+		}
+	}
+
+	add(payload.Task.AssigneeID)
+	add(payload.Task.CreatorID)
+	for _, change := range payload.Changes {
+		if change.Field != "assignee_id" {
+			continue
+		}
+		if previous, err := strconv.ParseUint(change.From, 10, 64); err == nil {
+			id := uint(previous)
+			add(&id)
+		}
+	}
+	return ids
+}
+
+// sendTaskEvent sends the email for a task event
+func (e *EmailService) sendTaskEvent(eventType string, task models.Task, userEmail string, details []ChangeDetail) error {
+	switch eventType {
+	case outbox.TaskCreated:
+		return e.SendTaskCreatedNotification(task, userEmail)
+	case outbox.TaskUpdated:
+		return e.SendTaskUpdatedNotification(task, userEmail, details)
+	}
+	return nil
+}
+
+// SendPendingNotification sends a notification that was held until the
+// user's quiet hours ended
+func (e *EmailService) SendPendingNotification(pending models.PendingNotification, userEmail string) error {
+	var payload TaskEventPayload
+	if err := json.Unmarshal(pending.Payload, &payload); err != nil {
+		return err
+	}
+	return e.sendTaskEvent(pending.EventType, payload.Task.model(), userEmail, emailChanges(payload.Changes))
+}
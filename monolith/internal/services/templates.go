@@ -0,0 +1,95 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// Email templates, one per email: NAME.html and NAME.txt each define the
+// "content" of layout.html and layout.txt, using the blocks in the partials.
+// HTML templates escape everything they insert.
+//
+//go:embed templates/*.html templates/*.txt
+var templateFiles embed.FS
+
+// emailTemplateNames lists the emails, by template file name
+var emailTemplateNames = []string{"task_created", "task_updated", "task_due_soon", "task_overdue", "daily_digest"}
+
+// emailData is what the templates render
+type emailData struct {
+	Title    string
+	Task     models.Task
+	Changes  []ChangeDetail
+	UserName string
+	Overdue  []models.Task
+	DueSoon  []models.Task
+	Updates  []digestUpdate
+}
+
+// digestUpdate is a task notification listed in the daily digest
+type digestUpdate struct {
+	// Kind is "Created" or "Updated"
+	Kind    string
+	Task    models.Task
+	Changes []ChangeDetail
+}
+
+var templateFuncs = map[string]interface{}{
+	"statusClass": func(status string) string {
+		return strings.ToLower(strings.ReplaceAll(status, " ", "-"))
+	},
+	"priorityClass": strings.ToLower,
+	"orDefault": func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+	"dueLabel": dueLabel,
+	"longDate": func(date *time.Time) string {
+		return date.Format("Monday, January 2, 2006")
+	},
+	"underline": func(title string) string {
+		return strings.Repeat("=", utf8.RuneCountInString(title))
+	},
+}
+
+var (
+	htmlTemplates = map[string]*htmltemplate.Template{}
+	textTemplates = map[string]*texttemplate.Template{}
+)
+
+func init() {
+	for _, name := range emailTemplateNames {
+		htmlTemplates[name] = htmltemplate.Must(htmltemplate.New(name).Funcs(templateFuncs).
+			ParseFS(templateFiles, "templates/layout.html", "templates/partials.html", "templates/"+name+".html"))
+		textTemplates[name] = texttemplate.Must(texttemplate.New(name).Funcs(templateFuncs).
+			ParseFS(templateFiles, "templates/layout.txt", "templates/partials.txt", "templates/"+name+".txt"))
+	}
+}
+
+// renderEmail renders an email's HTML body and its plain-text alternative
+func renderEmail(name string, data emailData) (string, string, error) {
+	var html, text bytes.Buffer
+	if err := htmlTemplates[name].ExecuteTemplate(&html, "layout.html", data); err != nil {
+		return "", "", err
+	}
+	if err := textTemplates[name].ExecuteTemplate(&text, "layout.txt", data); err != nil {
+		return "", "", err
+	}
+	return html.String(), text.String(), nil
+}
+
+// dueLabel is a short due date, such as "Mon, Jan 2"
+func dueLabel(task models.Task) string {
+	if task.DueDate == nil {
+		return "Soon"
+	}
+	return task.DueDate.Format("Mon, Jan 2")
+}
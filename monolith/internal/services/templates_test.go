@@ -0,0 +1,109 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/P4rz1val22/task-management-api/internal/mail"
+	"github.com/P4rz1val22/task-management-api/internal/models"
+)
+
+// testEmailData fills in everything any template renders, with title as
+// every piece of user-written text
+func testEmailData(title string) emailData {
+	due := time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+	task := models.Task{
+		ID:          7,
+		Title:       title,
+		Description: title,
+		Status:      "In Progress",
+		Priority:    "High",
+		Estimate:    "M",
+		DueDate:     &due,
+	}
+	changes := []ChangeDetail{{Field: "Title", From: title, To: title}, {Field: "Status", From: "Not Started", To: "In Progress"}}
+	return emailData{
+		Title:    "Test",
+		Task:     task,
+		Changes:  changes,
+		UserName: title,
+		Overdue:  []models.Task{task},
+		DueSoon:  []models.Task{task},
+		Updates:  []digestUpdate{{Kind: "Updated", Task: task, Changes: changes}},
+	}
+}
+
+func TestRenderEveryTemplate(t *testing.T) {
+	for _, name := range emailTemplateNames {
+		t.Run(name, func(t *testing.T) {
+			html, text, err := renderEmail(name, testEmailData("Water the plants"))
+			if err != nil {
+				t.Fatalf("renderEmail: %v", err)
+			}
+			if !strings.Contains(html, "</html>") {
+				t.Error("HTML part is not a complete document")
+			}
+			if !strings.Contains(html, "Water the plants") || !strings.Contains(text, "Water the plants") {
+				t.Error("the task title is missing")
+			}
+			if strings.Contains(html, "<no value>") || strings.Contains(text, "<no value>") {
+				t.Error("a template field rendered no value")
+			}
+		})
+	}
+}
+
+func TestRenderEmptyDigest(t *testing.T) {
+	html, text, err := renderEmail("daily_digest", emailData{Title: "Daily Digest", UserName: "Ada"})
+	if err != nil {
+		t.Fatalf("renderEmail: %v", err)
+	}
+	if !strings.Contains(html, "Nothing overdue") || !strings.Contains(text, "Ada") {
+		t.Error("the empty digest is missing its placeholders")
+	}
+}
+
+func TestTemplatesEscapeTaskText(t *testing.T) {
+	const title = `<script>alert("x")</script>`
+	for _, name := range emailTemplateNames {
+		t.Run(name, func(t *testing.T) {
+			html, text, err := renderEmail(name, testEmailData(title))
+			if err != nil {
+				t.Fatalf("renderEmail: %v", err)
+			}
+			if strings.Contains(html, "<script") {
+				t.Error("HTML part contains an unescaped <script> tag")
+			}
+			if !strings.Contains(html, "&lt;script&gt;") {
+				t.Error("HTML part is missing the escaped title")
+			}
+			// The plain-text part is not markup, so the title is kept as written
+			if !strings.Contains(text, title) {
+				t.Error("plain-text part is missing the title")
+			}
+		})
+	}
+}
+
+func TestSendTaskCreatedNotification(t *testing.T) {
+	mailer := mail.NewMemoryMailer()
+	service := &EmailService{Mailer: mailer, FromEmail: "Tasks <tasks@example.com>"}
+
+	task := models.Task{Title: "<b>Ship</b> it", Status: "Not Started"}
+	if err := service.SendTaskCreatedNotification(task, "ada@example.com"); err != nil {
+		t.Fatalf("SendTaskCreatedNotification: %v", err)
+	}
+
+	messages := mailer.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(messages))
+	}
+	msg := messages[0]
+	if msg.To != "ada@example.com" || msg.Subject != "✅ New Task Created: <b>Ship</b> it" {
+		t.Errorf("sent %q to %q", msg.Subject, msg.To)
+	}
+	if strings.Contains(msg.HTML, "<b>Ship</b>") || !strings.Contains(msg.HTML, "&lt;b&gt;Ship&lt;/b&gt;") {
+		t.Error("the title was not escaped in the HTML part")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/crypto/bcrypt"
 	"os"
+	"task-management-pkg/jwks"
 	"time"
 )
 
@@ -22,10 +23,11 @@ func CheckPassword(password string, hash string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
-func GenerateJWT(userID uint, email string) (string, error) {
+func GenerateJWT(userID uint, email string, role string) (string, error) {
 	claims := jwt.MapClaims{
 		"user_id": userID,
 		"email":   email,
+		"role":    role,
 		"exp":     time.Now().Add(time.Hour * 24).Unix(),
 		"iat":     time.Now().Unix(),
 	}
@@ -34,10 +36,22 @@ func GenerateJWT(userID uint, email string) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
+// ValidateJWT verifies a token against auth-service's published JWKS. Tokens
+// signed by the monolith's own login (HS256) are only accepted while
+// JWT_SECRET is set, for running the monolith standalone.
 func ValidateJWT(tokenString string) (jwt.MapClaims, error) {
+	validMethods := []string{"RS256", "EdDSA"}
+	if len(jwtSecret) > 0 {
+		validMethods = append(validMethods, "HS256")
+	}
+
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		if token.Method.Alg() == "HS256" {
+			return jwtSecret, nil
+		}
+		kid, _ := token.Header["kid"].(string)
+		return jwks.Key(kid)
+	}, jwt.WithValidMethods(validMethods))
 
 	if err != nil {
 		return nil, err
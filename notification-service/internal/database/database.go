@@ -0,0 +1,39 @@
+package database
+
+import (
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"task-management-notification-service/internal/models"
+)
+
+var DB *gorm.DB
+
+func Connect() {
+	// Load environment variables
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("Warning: .env file not found")
+	}
+
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		log.Fatal("DATABASE_URL environment variable is required")
+	}
+
+	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	// Users and revoked tokens are shared; deliveries belong to this service
+	err = DB.AutoMigrate(&models.User{}, &models.RevokedToken{}, &models.Delivery{})
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	log.Println("Notification Service: Database connected successfully!")
+}
@@ -0,0 +1,74 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// ErrNotConfigured is returned by Send when SMTP credentials are missing
+var ErrNotConfigured = errors.New("SMTP is not configured")
+
+// Mailer sends HTML email over SMTP
+type Mailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewMailer configures a mailer from SMTP_HOST (default smtp.gmail.com),
+// SMTP_PORT (default 587), SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM
+// (default the username)
+func NewMailer() *Mailer {
+	username := os.Getenv("SMTP_USERNAME")
+	mailer := &Mailer{
+		Host:     getEnv("SMTP_HOST", "smtp.gmail.com"),
+		Port:     getEnv("SMTP_PORT", "587"),
+		Username: username,
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     getEnv("SMTP_FROM", username),
+	}
+	if !mailer.configured() {
+		log.Println("Warning: SMTP_USERNAME or SMTP_PASSWORD not set, deliveries will fail until they are")
+	}
+	return mailer
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func (m *Mailer) configured() bool {
+	return m.Username != "" && m.Password != ""
+}
+
+// Send delivers one HTML email. Without SMTP credentials it fails with
+// ErrNotConfigured, so the delivery is retried rather than recorded as sent.
+func (m *Mailer) Send(to, subject, body string) error {
+	if !m.configured() {
+		return ErrNotConfigured
+	}
+
+	auth := smtp.PlainAuth("", m.Username, m.Password, m.Host)
+
+	msg := []string{
+		fmt.Sprintf("To: %s", to),
+		fmt.Sprintf("From: %s", m.From),
+		fmt.Sprintf("Subject: %s", mime.QEncoding.Encode("utf-8", subject)),
+		"MIME-Version: 1.0",
+		"Content-Type: text/html; charset=\"UTF-8\"",
+		"",
+		body,
+	}
+
+	return smtp.SendMail(m.Host+":"+m.Port, auth, m.From, []string{to}, []byte(strings.Join(msg, "\r\n")))
+}
@@ -0,0 +1,13 @@
+package delivery
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSendWithoutCredentialsFails(t *testing.T) {
+	mailer := &Mailer{Host: "smtp.example.com", Port: "587"}
+	if err := mailer.Send("ada@example.com", "Subject", "<p>Body</p>"); !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Send = %v, want ErrNotConfigured", err)
+	}
+}
@@ -0,0 +1,111 @@
+package delivery
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"os"
+	"strconv"
+	"task-management-notification-service/internal/database"
+	"task-management-notification-service/internal/models"
+	"task-management-pkg/worker"
+	"time"
+)
+
+const (
+	// batchSize bounds how many due deliveries one run picks up
+	batchSize = 100
+)
+
+// backoff spaces out retries: 30s after the first failure, doubling up to an hour
+var backoff = worker.Backoff{First: 30 * time.Second, Max: time.Hour}
+
+// loop runs the worker; intake wakes it rather than waiting for the next tick
+var loop = worker.NewLoop()
+
+// Wake asks the worker to look for due deliveries now
+func Wake() {
+	loop.Wake()
+}
+
+// Worker sends pending deliveries, retrying failures with exponential
+// backoff until MaxAttempts is reached
+type Worker struct {
+	Mailer      *Mailer
+	MaxAttempts int
+}
+
+// NewWorker configures a worker from DELIVERY_MAX_ATTEMPTS (default 5)
+func NewWorker(mailer *Mailer) *Worker {
+	maxAttempts, err := strconv.Atoi(os.Getenv("DELIVERY_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 5
+	}
+	return &Worker{Mailer: mailer, MaxAttempts: maxAttempts}
+}
+
+// Start sends due deliveries every interval, or sooner when woken, in the
+// background
+func (w *Worker) Start(interval time.Duration) {
+	loop.Start(interval, func() { w.run(time.Now()) })
+}
+
+func (w *Worker) run(now time.Time) {
+	var deliveryIDs []uint
+	err := database.DB.Model(&models.Delivery{}).
+		Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+		Order("next_attempt_at, id").
+		Limit(batchSize).
+		Pluck("id", &deliveryIDs).Error
+	if err != nil {
+		log.Printf("Delivery worker: failed to find due deliveries: %v", err)
+		return
+	}
+
+	for _, deliveryID := range deliveryIDs {
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			return w.deliver(tx, deliveryID, now)
+		})
+		if err != nil {
+			log.Printf("Delivery worker: failed to process delivery %d: %v", deliveryID, err)
+		}
+	}
+}
+
+// deliver attempts one delivery and records the outcome. The row stays
+// locked while sending, and rows another worker holds are skipped.
+func (w *Worker) deliver(tx *gorm.DB, deliveryID uint, now time.Time) error {
+	var delivery models.Delivery
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", deliveryID, models.DeliveryPending, now).
+		First(&delivery).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	delivery.Attempts++
+	sendErr := w.Mailer.Send(delivery.Email, delivery.Subject, delivery.Body)
+
+	updates := map[string]interface{}{"attempts": delivery.Attempts}
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.DeliverySent
+		updates["sent_at"] = time.Now()
+		updates["last_error"] = ""
+		log.Printf("📧 EMAIL SENT: %s to %s", delivery.Subject, delivery.Email)
+	case delivery.Attempts >= w.MaxAttempts:
+		updates["status"] = models.DeliveryFailed
+		updates["last_error"] = sendErr.Error()
+		log.Printf("❌ Delivery %d failed for good after %d attempts: %v", delivery.ID, delivery.Attempts, sendErr)
+	default:
+		updates["last_error"] = sendErr.Error()
+		updates["next_attempt_at"] = now.Add(backoff.Delay(delivery.Attempts))
+		log.Printf("❌ Delivery %d failed (attempt %d), retrying: %v", delivery.ID, delivery.Attempts, sendErr)
+	}
+
+	return tx.Model(&delivery).Updates(updates).Error
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"task-management-notification-service/internal/database"
+	"task-management-notification-service/internal/delivery"
+	"task-management-notification-service/internal/models"
+	"task-management-pkg/pagination"
+	"time"
+)
+
+// deliverySortColumns are the fields GET /admin/notifications/deliveries can be sorted by
+var deliverySortColumns = map[string]string{
+	"id":              "deliveries.id",
+	"created_at":      "deliveries.created_at",
+	"next_attempt_at": "deliveries.next_attempt_at",
+	"attempts":        "deliveries.attempts",
+}
+
+// AdminGetDeliveries lists the delivery log, newest first, filtered by
+// ?status=, ?event_type=, ?task_id= and ?recipient_id=
+func AdminGetDeliveries(c *gin.Context) {
+	page, err := pagination.Parse(c, "deliveries", deliverySortColumns, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := database.DB.Model(&models.Delivery{})
+	if status := c.Query("status"); status != "" {
+		if status != models.DeliveryPending && status != models.DeliverySent && status != models.DeliveryFailed {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: pending, sent, failed"})
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	if taskID := c.Query("task_id"); taskID != "" {
+		query = query.Where("task_id = ?", taskID)
+	}
+	if recipientID := c.Query("recipient_id"); recipientID != "" {
+		query = query.Where("recipient_id = ?", recipientID)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	var deliveries []models.Delivery
+	if err := page.Apply(query).Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	hasMore := len(deliveries) > page.Limit
+	if hasMore {
+		deliveries = deliveries[:page.Limit]
+	}
+
+	var lastID uint
+	if len(deliveries) > 0 {
+		lastID = deliveries[len(deliveries)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries":  deliveries,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": pagination.NextCursor(hasMore, lastID),
+	})
+}
+
+// AdminGetDelivery shows one delivery, including the rendered email
+func AdminGetDelivery(c *gin.Context) {
+	var delivery models.Delivery
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&delivery).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delivery": delivery,
+		"body":     delivery.Body,
+	})
+}
+
+// AdminRetryDelivery puts a failed delivery back in the queue with a fresh
+// set of attempts
+func AdminRetryDelivery(c *gin.Context) {
+	var failed models.Delivery
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&failed).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	// Only failed deliveries are retried, so one being sent isn't doubled
+	result := database.DB.Model(&failed).Where("status = ?", models.DeliveryFailed).Updates(map[string]interface{}{
+		"status":          models.DeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retry delivery"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only failed deliveries can be retried"})
+		return
+	}
+
+	delivery.Wake()
+
+	database.DB.First(&failed, failed.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Delivery queued for retry",
+		"delivery": failed,
+	})
+}
+
+// HealthCheck reports service health
+func HealthCheck(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"service": "notification-service",
+		"status":  "healthy",
+		"port":    "8085",
+		"message": "Notification service is running",
+	})
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"strconv"
+	"task-management-notification-service/internal/database"
+	"task-management-notification-service/internal/delivery"
+	"task-management-notification-service/internal/models"
+	"task-management-notification-service/internal/templates"
+	"time"
+)
+
+// ReceiveEvent queues emails for the people a task event concerns. Events
+// are idempotent by ID, so publishers may safely resend them.
+func ReceiveEvent(c *gin.Context) {
+	var event models.Event
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var subject, body string
+	switch event.Type {
+	case models.TaskCreated:
+		subject, body = templates.TaskCreated(event.Task)
+	case models.TaskUpdated:
+		subject, body = templates.TaskUpdated(event.Task, event.Changes)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event type. Use: task.created, task.updated"})
+		return
+	}
+
+	var recipients []models.User
+	if ids := recipientIDs(event); len(ids) > 0 {
+		if err := database.DB.Where("id IN ?", ids).Find(&recipients).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve recipients"})
+			return
+		}
+	}
+
+	now := time.Now()
+	var queued int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		for _, recipient := range recipients {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.Delivery{
+				EventID:       event.ID,
+				EventType:     event.Type,
+				TaskID:        event.Task.ID,
+				RecipientID:   recipient.ID,
+				Email:         recipient.Email,
+				Subject:       subject,
+				Body:          body,
+				Status:        models.DeliveryPending,
+				NextAttemptAt: now,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			queued += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue notifications"})
+		return
+	}
+
+	if queued > 0 {
+		delivery.Wake()
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Event accepted",
+		"queued":  queued,
+	})
+}
+
+// recipientIDs are the task's assignee and creator, plus the previous
+// assignee when the event reassigned it. The actor already knows about
+// their own change and is left out.
+func recipientIDs(event models.Event) []uint {
+	candidates := []*uint{event.Task.AssigneeID, event.Task.CreatorID}
+	for _, change := range event.Changes {
+		if change.Field != "assignee_id" || change.From == "" {
+			continue
+		}
+		if id, err := strconv.ParseUint(change.From, 10, 64); err == nil {
+			previous := uint(id)
+			candidates = append(candidates, &previous)
+		}
+	}
+
+	seen := map[uint]bool{event.ActorID: true}
+	var ids []uint
+	for _, id := range candidates {
+		if id != nil && !seen[*id] {
+			seen[*id] = true
+			ids = append(ids, *id)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderServiceToken carries the shared secret of internal publishers
+const HeaderServiceToken = "X-Service-Token"
+
+// RequireServiceToken only lets through requests carrying
+// NOTIFICATION_SERVICE_TOKEN. When it is unset every request is let through,
+// so the intake must only be reachable from the internal network.
+func RequireServiceToken() gin.HandlerFunc {
+	token := []byte(os.Getenv("NOTIFICATION_SERVICE_TOKEN"))
+	if len(token) == 0 {
+		log.Println("Warning: NOTIFICATION_SERVICE_TOKEN not set, event intake is unauthenticated")
+	}
+
+	return func(c *gin.Context) {
+		if len(token) > 0 && !hmac.Equal([]byte(c.GetHeader(HeaderServiceToken)), token) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid service token"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
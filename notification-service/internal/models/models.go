@@ -0,0 +1,92 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type User struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	Name      string         `json:"name" gorm:"not null"`
+	Email     string         `json:"email" gorm:"uniqueIndex;not null"`
+	Password  string         `json:"-" gorm:"not null"`
+	Role      string         `json:"role" gorm:"default:user"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// RevokedToken is the shared denylist of access token IDs (jti claims),
+// written by auth-service on logout
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Event types published by task-service
+const (
+	TaskCreated = "task.created"
+	TaskUpdated = "task.updated"
+)
+
+// Event is a task change published to the notification service. ID is
+// chosen by the publisher and makes redelivered events harmless; ActorID is
+// 0 for changes no user made, such as scheduled ones.
+type Event struct {
+	ID         string        `json:"id" binding:"required"`
+	Type       string        `json:"type" binding:"required"`
+	ActorID    uint          `json:"actor_id"`
+	Task       TaskSnapshot  `json:"task" binding:"required"`
+	Changes    []FieldChange `json:"changes"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// TaskSnapshot is the state of a task after the event
+type TaskSnapshot struct {
+	ID          uint       `json:"id" binding:"required"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   uint       `json:"project_id"`
+	AssigneeID  *uint      `json:"assignee_id"`
+	CreatorID   *uint      `json:"creator_id"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	Estimate    string     `json:"estimate"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+// FieldChange is a field's value before and after an event. Values are
+// rendered as strings; empty means unset.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// Delivery statuses
+const (
+	DeliveryPending = "pending"
+	DeliverySent    = "sent"
+	DeliveryFailed  = "failed"
+)
+
+// Delivery is one email queued for one recipient of an event. Pending
+// deliveries are the queue; sent and failed ones are the delivery log.
+type Delivery struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	EventID       string     `json:"event_id" gorm:"not null;uniqueIndex:idx_delivery_event"`
+	EventType     string     `json:"event_type" gorm:"not null"`
+	TaskID        uint       `json:"task_id" gorm:"not null;index"`
+	RecipientID   uint       `json:"recipient_id" gorm:"not null;uniqueIndex:idx_delivery_event"`
+	Email         string     `json:"email" gorm:"not null"`
+	Subject       string     `json:"subject" gorm:"not null"`
+	Body          string     `json:"-" gorm:"type:text;not null"`
+	Status        string     `json:"status" gorm:"not null;default:pending;index"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"task-management-notification-service/internal/database"
+	"task-management-notification-service/internal/delivery"
+	"task-management-notification-service/internal/handlers"
+	"task-management-notification-service/internal/middleware"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func main() {
+	// Connect to database
+	database.Connect()
+
+	// Send queued emails in the background
+	delivery.NewWorker(delivery.NewMailer()).Start(deliveryInterval())
+
+	// Set Gin mode
+	gin.SetMode(gin.ReleaseMode)
+
+	r := gin.New()
+
+	// Add logging middleware
+	r.Use(gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		return fmt.Sprintf("[NOTIFICATION-SERVICE] %s - %s %s %d %s\n",
+			param.TimeStamp.Format("15:04:05"),
+			param.Method,
+			param.Path,
+			param.StatusCode,
+			param.Latency,
+		)
+	}))
+
+	// Add recovery middleware
+	r.Use(gin.Recovery())
+
+	// Health check
+	r.GET("/health", handlers.HealthCheck)
+
+	// Event intake for internal publishers (not routed by the gateway)
+	r.POST("/events", middleware.RequireServiceToken(), handlers.ReceiveEvent)
+
+	// Delivery log (admin role only)
+	admin := r.Group("/admin/notifications")
+	admin.Use(middleware.RequireAuth(), middleware.RequireRole("admin"))
+	{
+		admin.GET("/deliveries", handlers.AdminGetDeliveries)
+		admin.GET("/deliveries/:id", handlers.AdminGetDelivery)
+		admin.POST("/deliveries/:id/retry", handlers.AdminRetryDelivery)
+	}
+
+	log.Println("📬 Notification Service starting on port 8085")
+	log.Println("📋 Available endpoints:")
+	log.Println("   GET  /health")
+	log.Println("   POST /events (internal)")
+	log.Println("   GET  /admin/notifications/deliveries (with filtering: ?status=X&event_type=Y&task_id=T&recipient_id=R)")
+	log.Println("   GET  /admin/notifications/deliveries/:id")
+	log.Println("   POST /admin/notifications/deliveries/:id/retry")
+
+	if err := r.Run(":8085"); err != nil {
+		log.Fatal("Failed to start notification service:", err)
+	}
+}
+
+// deliveryInterval reads how often the queue is polled from
+// DELIVERY_INTERVAL (e.g. 5s, 1m), defaulting to every 10 seconds. New
+// events are sent right away regardless.
+func deliveryInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("DELIVERY_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 10 * time.Second
+}
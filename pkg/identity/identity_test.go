@@ -0,0 +1,47 @@
+package identity
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func signedHeaders(secret []byte, userID string, signedAt time.Time) http.Header {
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	header := http.Header{}
+	header.Set(HeaderUserID, userID)
+	header.Set(HeaderUserEmail, "ada@example.com")
+	header.Set(HeaderUserRole, "user")
+	header.Set(HeaderTokenID, "token-1")
+	header.Set(HeaderTimestamp, timestamp)
+	header.Set(HeaderSignature, Sign(secret, userID, "ada@example.com", "user", "token-1", timestamp))
+	return header
+}
+
+func TestClaims(t *testing.T) {
+	identitySecret = []byte("secret")
+	t.Cleanup(func() { identitySecret = nil })
+
+	claims, err := Claims(signedHeaders(identitySecret, "7", time.Now()).Get)
+	if err != nil {
+		t.Fatalf("Claims: %v", err)
+	}
+	if claims["user_id"] != float64(7) || claims["email"] != "ada@example.com" || claims["jti"] != "token-1" {
+		t.Errorf("claims = %v", claims)
+	}
+
+	tampered := signedHeaders(identitySecret, "7", time.Now())
+	tampered.Set(HeaderUserRole, "admin")
+	tests := map[string]http.Header{
+		"wrong secret": signedHeaders([]byte("other"), "7", time.Now()),
+		"expired":      signedHeaders(identitySecret, "7", time.Now().Add(-time.Hour)),
+		"tampered":     tampered,
+		"no user":      signedHeaders(identitySecret, "", time.Now()),
+	}
+	for name, header := range tests {
+		if _, err := Claims(header.Get); err == nil {
+			t.Errorf("%s: Claims accepted the headers", name)
+		}
+	}
+}
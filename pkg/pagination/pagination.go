@@ -0,0 +1,164 @@
+// Package pagination reads the ?limit=, ?cursor= or ?offset=, and ?sort=
+// parameters of list requests and applies them to queries
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+// Page is a list request's ?limit=, ?cursor= or ?offset=, and ?sort=
+type Page struct {
+	// Limit is how many rows the page holds
+	Limit  int
+	table  string
+	offset int
+	after  uint // id of the last row of the previous page
+	order  []sortKey
+}
+
+type sortKey struct {
+	expr string
+	desc bool
+}
+
+// Parse reads the paging params of a list request. columns maps the
+// names ?sort= accepts to SQL expressions over table; rows are always finally
+// ordered by id so pages are stable.
+func Parse(c *gin.Context, table string, columns map[string]string, defaultSort string) (Page, error) {
+	p := Page{Limit: defaultPageLimit, table: table}
+
+	if limit := c.Query("limit"); limit != "" {
+		value, err := strconv.Atoi(limit)
+		if err != nil || value <= 0 {
+			return p, errors.New("Invalid limit. Use a positive number")
+		}
+		p.Limit = min(value, maxPageLimit)
+	}
+
+	cursor, offset := c.Query("cursor"), c.Query("offset")
+	if cursor != "" && offset != "" {
+		return p, errors.New("Use either cursor or offset, not both")
+	}
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return p, errors.New("Invalid cursor")
+		}
+		p.after = after
+	}
+	if offset != "" {
+		value, err := strconv.Atoi(offset)
+		if err != nil || value < 0 {
+			return p, errors.New("Invalid offset. Use a non-negative number")
+		}
+		p.offset = value
+	}
+
+	sortParam := c.DefaultQuery("sort", defaultSort)
+	for _, field := range strings.Split(sortParam, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		desc := strings.HasPrefix(field, "-")
+		expr, ok := columns[strings.TrimPrefix(field, "-")]
+		if !ok {
+			return p, fmt.Errorf("Invalid sort field %q. Use: %s", strings.TrimPrefix(field, "-"), sortFieldNames(columns))
+		}
+		p.order = append(p.order, sortKey{expr: expr, desc: desc})
+	}
+
+	return p, nil
+}
+
+func sortFieldNames(columns map[string]string) string {
+	names := make([]string, 0, len(columns))
+	for name := range columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Apply orders the query, skips to the requested page and fetches one row
+// more than the limit so callers can tell whether another page follows
+func (p Page) Apply(query *gorm.DB) *gorm.DB {
+	for _, key := range p.order {
+		if key.desc {
+			query = query.Order(key.expr + " DESC")
+		} else {
+			query = query.Order(key.expr)
+		}
+	}
+	query = query.Order(p.table + ".id")
+
+	if p.after != 0 {
+		condition, args := p.cursorCondition()
+		query = query.Where(condition, args...)
+	} else if p.offset > 0 {
+		query = query.Offset(p.offset)
+	}
+
+	return query.Limit(p.Limit + 1)
+}
+
+// cursorCondition selects the rows sorting after the cursor row, comparing
+// against that row's current values: (a > a0) OR (a = a0 AND b < b0) OR ...
+func (p Page) cursorCondition() (string, []interface{}) {
+	keys := append(append([]sortKey(nil), p.order...), sortKey{expr: p.table + ".id"})
+
+	var terms []string
+	var args []interface{}
+	for i := range keys {
+		var parts []string
+		for j, previous := range keys[:i+1] {
+			operator := "="
+			if j == i {
+				operator = ">"
+				if previous.desc {
+					operator = "<"
+				}
+			}
+			parts = append(parts, fmt.Sprintf("%s %s (SELECT %s FROM %s WHERE %s.id = ?)",
+				previous.expr, operator, previous.expr, p.table, p.table))
+			args = append(args, p.after)
+		}
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return strings.Join(terms, " OR "), args
+}
+
+// NextCursor returns the cursor for the page after the row lastID, or nil
+// when this was the last page
+func NextCursor(hasMore bool, lastID uint) interface{} {
+	if !hasMore {
+		return nil
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(lastID), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil || id == 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return uint(id), nil
+}
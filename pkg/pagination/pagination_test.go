@@ -0,0 +1,59 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var columns = map[string]string{"title": "tasks.title", "due": "tasks.due_date"}
+
+func parse(t *testing.T, query string) (Page, error) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/tasks?"+query, nil)
+	return Parse(c, "tasks", columns, "id")
+}
+
+func TestParse(t *testing.T) {
+	page, err := parse(t, "limit=500&cursor="+NextCursor(true, 42).(string)+"&sort=-due,title")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if page.Limit != maxPageLimit || page.after != 42 {
+		t.Errorf("limit %d, after %d; want %d, 42", page.Limit, page.after, maxPageLimit)
+	}
+	want := []sortKey{{expr: "tasks.due_date", desc: true}, {expr: "tasks.title"}}
+	if !reflect.DeepEqual(page.order, want) {
+		t.Errorf("order = %v, want %v", page.order, want)
+	}
+
+	for _, query := range []string{"limit=0", "offset=-1", "cursor=xyz", "cursor=MQ&offset=1", "sort=secret"} {
+		if _, err := parse(t, query); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", query)
+		}
+	}
+}
+
+func TestCursorCondition(t *testing.T) {
+	page := Page{table: "tasks", after: 7, order: []sortKey{{expr: "tasks.due_date", desc: true}}}
+	condition, args := page.cursorCondition()
+
+	want := "(tasks.due_date < (SELECT tasks.due_date FROM tasks WHERE tasks.id = ?)) OR " +
+		"(tasks.due_date = (SELECT tasks.due_date FROM tasks WHERE tasks.id = ?) AND tasks.id > (SELECT tasks.id FROM tasks WHERE tasks.id = ?))"
+	if condition != want {
+		t.Errorf("condition:\n got %s\nwant %s", condition, want)
+	}
+	if len(args) != strings.Count(want, "?") {
+		t.Errorf("%d args for %d placeholders", len(args), strings.Count(want, "?"))
+	}
+}
+
+func TestNextCursorOnLastPage(t *testing.T) {
+	if cursor := NextCursor(false, 42); cursor != nil {
+		t.Errorf("NextCursor = %v on the last page, want nil", cursor)
+	}
+}
@@ -0,0 +1,57 @@
+// Package worker holds what the services' background workers share: a
+// polling loop that can be woken early, and exponential retry backoff
+package worker
+
+import "time"
+
+// Backoff is an exponential retry schedule: the wait after the first failed
+// attempt is First, doubling with every further failure up to Max
+type Backoff struct {
+	First time.Duration
+	Max   time.Duration
+}
+
+// Delay is the wait before the next attempt after the given number of
+// failed attempts
+func (b Backoff) Delay(attempts int) time.Duration {
+	delay := b.First
+	for i := 1; i < attempts && delay < b.Max; i++ {
+		delay *= 2
+	}
+	return min(delay, b.Max)
+}
+
+// Loop runs a worker every interval, or sooner when woken
+type Loop struct {
+	wake chan struct{}
+}
+
+// NewLoop creates a loop that isn't running yet
+func NewLoop() *Loop {
+	return &Loop{wake: make(chan struct{}, 1)}
+}
+
+// Wake asks for a run now. Wakes during a run make one more run after it.
+func (l *Loop) Wake() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start calls run right away, then every interval or sooner when woken, in
+// the background
+func (l *Loop) Start(interval time.Duration, run func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			run()
+			select {
+			case <-ticker.C:
+			case <-l.wake:
+			}
+		}
+	}()
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{First: 30 * time.Second, Max: time.Hour}
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{50, time.Hour},
+	}
+	for _, test := range tests {
+		if got := backoff.Delay(test.attempts); got != test.want {
+			t.Errorf("Delay(%d) = %v, want %v", test.attempts, got, test.want)
+		}
+	}
+}
+
+func TestLoopRunsWhenWoken(t *testing.T) {
+	runs := make(chan struct{}, 10)
+	loop := NewLoop()
+	loop.Start(time.Hour, func() { runs <- struct{}{} })
+
+	for i := range 3 {
+		select {
+		case <-runs:
+		case <-time.After(time.Second):
+			t.Fatalf("run %d didn't happen", i+1)
+		}
+		loop.Wake()
+	}
+}
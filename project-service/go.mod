@@ -46,4 +46,7 @@ require (
 	golang.org/x/text v0.21.0 // indirect
 	google.golang.org/protobuf v1.34.1 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
+	task-management-pkg v0.0.0
 )
+
+replace task-management-pkg => ../pkg
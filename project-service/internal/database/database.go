@@ -31,10 +31,23 @@ func Connect() {
 
 	// In shared DB strategy, we only ensure our tables exist
 	// (they should already exist from monolith)
-	err = DB.AutoMigrate(&models.User{}, &models.Project{}, &models.Task{})
+	err = DB.AutoMigrate(&models.User{}, &models.Project{}, &models.Task{}, &models.ProjectMember{}, &models.RevokedToken{}, &models.Webhook{}, &models.WebhookDelivery{})
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// Projects created before workflows existed get the default one
+	err = DB.Model(&models.Project{}).Where("workflow IS NULL").UpdateColumn("workflow", models.DefaultWorkflow()).Error
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Webhook responses used to be kept; drop what was stored
+	if DB.Migrator().HasColumn(&models.WebhookDelivery{}, "response_body") {
+		if err := DB.Migrator().DropColumn(&models.WebhookDelivery{}, "response_body"); err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+	}
+
 	log.Println("Project Service: Database connected successfully!")
 }
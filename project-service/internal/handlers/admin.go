@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"task-management-project-service/internal/database"
+	"task-management-project-service/internal/models"
+)
+
+// AdminGetProjects lists every project regardless of ownership, optionally filtered by ?owner_id=
+func AdminGetProjects(c *gin.Context) {
+	query := database.DB.Preload("Owner").Order("id")
+	if ownerID := c.Query("owner_id"); ownerID != "" {
+		query = query.Where("owner_id = ?", ownerID)
+	}
+
+	var projects []models.Project
+	if err := query.Find(&projects).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+
+	projectList := make([]gin.H, 0, len(projects))
+	for _, project := range projects {
+		projectList = append(projectList, gin.H{
+			"id":          project.ID,
+			"name":        project.Name,
+			"description": project.Description,
+			"owner_id":    project.OwnerID,
+			"owner":       project.Owner.Name,
+			"created_at":  project.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"projects": projectList,
+	})
+}
+
+// AdminGetProjectByID shows any project with its members, for supporting users
+func AdminGetProjectByID(c *gin.Context) {
+	projectID := c.Param("id")
+
+	var project models.Project
+	if err := database.DB.Preload("Owner").Preload("Tasks").Where("id = ?", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	var members []models.ProjectMember
+	if err := database.DB.Preload("User").Where("project_id = ?", project.ID).Order("created_at").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project members"})
+		return
+	}
+
+	memberList := make([]gin.H, 0, len(members))
+	for _, member := range members {
+		memberList = append(memberList, gin.H{
+			"user_id": member.UserID,
+			"name":    member.User.Name,
+			"email":   member.User.Email,
+			"role":    member.Role,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project": gin.H{
+			"id":          project.ID,
+			"name":        project.Name,
+			"description": project.Description,
+			"owner_id":    project.OwnerID,
+			"owner":       project.Owner.Name,
+			"owner_email": project.Owner.Email,
+			"members":     memberList,
+			"task_count":  len(project.Tasks),
+			"created_at":  project.CreatedAt,
+			"updated_at":  project.UpdatedAt,
+		},
+	})
+}
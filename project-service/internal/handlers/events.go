@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"slices"
+	"task-management-project-service/internal/database"
+	"task-management-project-service/internal/models"
+	"task-management-project-service/internal/webhooks"
+)
+
+// ReceiveTaskEvent queues a task event published by task-service for the
+// webhooks of the task's project. Events are idempotent by ID, so publishers
+// may safely resend them.
+func ReceiveTaskEvent(c *gin.Context) {
+	var event models.TaskEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !slices.Contains([]string{models.TaskCreated, models.TaskUpdated, models.TaskDeleted}, event.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event type. Use: task.created, task.updated, task.deleted"})
+		return
+	}
+
+	var queued int64
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		queued, err = webhooks.Enqueue(tx, event.Task.ProjectID, event.Type, event.ID, event.ActorID, event.OccurredAt,
+			webhooks.TaskData{Task: event.Task, Changes: event.Changes})
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue webhook deliveries"})
+		return
+	}
+
+	if queued > 0 {
+		webhooks.Wake()
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Event accepted",
+		"queued":  queued,
+	})
+}
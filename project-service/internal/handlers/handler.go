@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"net/http"
+	"task-management-pkg/pagination"
 	"task-management-project-service/internal/database"
 	"task-management-project-service/internal/models"
+	"task-management-project-service/internal/webhooks"
 )
 
 type ProjectRequest struct {
@@ -12,6 +15,14 @@ type ProjectRequest struct {
 	Description string `json:"description"`
 }
 
+// projectSortColumns are the fields GET /projects can be sorted by
+var projectSortColumns = map[string]string{
+	"id":         "projects.id",
+	"name":       "projects.name",
+	"created_at": "projects.created_at",
+	"updated_at": "projects.updated_at",
+}
+
 // CreateProject handles project creation
 func CreateProject(c *gin.Context) {
 	userID := c.GetUint("user_id")
@@ -28,14 +39,25 @@ func CreateProject(c *gin.Context) {
 		return
 	}
 
-	// Create a project
+	// Create a project, recording the creator as its owner member
 	project := models.Project{
 		Name:        req.Name,
 		Description: req.Description,
 		OwnerID:     userID,
+		Workflow:    models.DefaultWorkflow(),
 	}
 
-	if err := database.DB.Create(&project).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ProjectMember{
+			ProjectID: project.ID,
+			UserID:    userID,
+			Role:      models.RoleOwner,
+		}).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
 		return
 	}
@@ -53,30 +75,58 @@ func CreateProject(c *gin.Context) {
 	})
 }
 
-// GetProjects handles listing all projects for authenticated user
+// GetProjects handles listing all projects the authenticated user owns or is a member of
 func GetProjects(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
+	page, err := pagination.Parse(c, "projects", projectSortColumns, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := database.DB.Model(&models.Project{}).Where("id IN (?)", accessibleProjectIDs(userID)).Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+
 	var projects []models.Project
-	if err := database.DB.Preload("Owner").Where("owner_id = ?", userID).Find(&projects).Error; err != nil {
+	if err := page.Apply(query.Preload("Owner")).Find(&projects).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
 		return
 	}
 
+	hasMore := len(projects) > page.Limit
+	if hasMore {
+		projects = projects[:page.Limit]
+	}
+
 	var projectList []gin.H
 	for _, project := range projects {
+		role, _ := projectRole(project, userID)
 		projectList = append(projectList, gin.H{
 			"id":          project.ID,
 			"name":        project.Name,
 			"description": project.Description,
 			"owner_id":    project.OwnerID,
 			"owner":       project.Owner.Name,
+			"role":        role,
 			"created_at":  project.CreatedAt,
 		})
 	}
 
+	var lastID uint
+	if len(projects) > 0 {
+		lastID = projects[len(projects)-1].ID
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"projects": projectList,
+		"projects":    projectList,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": pagination.NextCursor(hasMore, lastID),
 	})
 }
 
@@ -87,7 +137,13 @@ func GetProjectByID(c *gin.Context) {
 	projectID := c.Param("id")
 
 	var project models.Project
-	if err := database.DB.Preload("Owner").Preload("Tasks").Where("id = ? AND owner_id = ?", projectID, userID).First(&project).Error; err != nil {
+	if err := database.DB.Preload("Owner").Preload("Tasks").Where("id = ?", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	role, ok := projectRole(project, userID)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
@@ -99,7 +155,9 @@ func GetProjectByID(c *gin.Context) {
 			"description": project.Description,
 			"owner_id":    project.OwnerID,
 			"owner":       project.Owner.Name,
+			"role":        role,
 			"task_count":  len(project.Tasks),
+			"workflow":    project.Workflow,
 			"created_at":  project.CreatedAt,
 			"updated_at":  project.UpdatedAt,
 		},
@@ -118,24 +176,54 @@ func UpdateProject(c *gin.Context) {
 	}
 
 	var project models.Project
-	if err := database.DB.Where("id = ? AND owner_id = ?", projectID, userID).First(&project).Error; err != nil {
+	if err := database.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		return
 	}
 
+	role, ok := projectRole(project, userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if !canEdit(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only project owners and editors can update this project"})
+		return
+	}
+
 	var existingProject models.Project
-	if err := database.DB.Where("name = ? AND owner_id = ? AND id != ?", req.Name, userID, projectID).First(&existingProject).Error; err == nil {
+	if err := database.DB.Where("name = ? AND owner_id = ? AND id != ?", req.Name, project.OwnerID, projectID).First(&existingProject).Error; err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "Project name already exists"})
 		return
 	}
 
+	var changes []models.FieldChange
+	if req.Name != project.Name {
+		changes = append(changes, models.FieldChange{Field: "name", From: project.Name, To: req.Name})
+	}
+	if req.Description != project.Description {
+		changes = append(changes, models.FieldChange{Field: "description", From: project.Description, To: req.Description})
+	}
+
 	project.Name = req.Name
 	project.Description = req.Description
 
-	if err := database.DB.Save(&project).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&project).Error; err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		data := webhooks.NewProjectData(project)
+		data.Changes = changes
+		return webhooks.Record(tx, models.ProjectUpdated, userID, data)
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
 		return
 	}
+	webhooks.Wake()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Project updated successfully",
@@ -172,10 +260,20 @@ func DeleteProject(c *gin.Context) {
 	}
 
 	// Safe to delete - no tasks exist
-	if err := database.DB.Delete(&project).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", project.ID).Delete(&models.ProjectMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&project).Error; err != nil {
+			return err
+		}
+		return webhooks.Record(tx, models.ProjectDeleted, userID, webhooks.NewProjectData(project))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
 		return
 	}
+	webhooks.Wake()
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Project deleted successfully",
@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"task-management-project-service/internal/database"
+	"task-management-project-service/internal/models"
+	"task-management-project-service/internal/webhooks"
+)
+
+type MemberRequest struct {
+	UserID uint   `json:"user_id"`
+	Email  string `json:"email"`
+	Role   string `json:"role" binding:"required"`
+}
+
+// accessibleProjectIDs builds a subquery of project IDs the user owns or is a member of
+func accessibleProjectIDs(userID uint) *gorm.DB {
+	memberProjects := database.DB.Model(&models.ProjectMember{}).Select("project_id").Where("user_id = ?", userID)
+	return database.DB.Model(&models.Project{}).Select("id").Where("owner_id = ? OR id IN (?)", userID, memberProjects)
+}
+
+// projectRole returns the user's role on a project. The project owner is
+// always treated as an owner, even without a member row.
+func projectRole(project models.Project, userID uint) (string, bool) {
+	if project.OwnerID == userID {
+		return models.RoleOwner, true
+	}
+
+	var member models.ProjectMember
+	if err := database.DB.Where("project_id = ? AND user_id = ?", project.ID, userID).First(&member).Error; err != nil {
+		return "", false
+	}
+	return member.Role, true
+}
+
+func canEdit(role string) bool {
+	return role == models.RoleOwner || role == models.RoleEditor
+}
+
+func isValidMemberRole(role string) bool {
+	return role == models.RoleEditor || role == models.RoleViewer
+}
+
+// AddProjectMember adds a user to a project, or changes their role if already a member
+func AddProjectMember(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	projectID := c.Param("id")
+
+	var req MemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !isValidMemberRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role. Use: editor, viewer"})
+		return
+	}
+	if req.UserID == 0 && req.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Either user_id or email is required"})
+		return
+	}
+
+	// Only the owner manages membership
+	var project models.Project
+	if err := database.DB.Where("id = ? AND owner_id = ?", projectID, userID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or access denied"})
+		return
+	}
+
+	var user models.User
+	query := database.DB
+	if req.UserID != 0 {
+		query = query.Where("id = ?", req.UserID)
+	} else {
+		query = query.Where("email = ?", req.Email)
+	}
+	if err := query.First(&user).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	if user.ID == project.OwnerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The project owner's role cannot be changed"})
+		return
+	}
+
+	status := http.StatusOK
+	eventType := models.ProjectMemberUpdated
+	var member models.ProjectMember
+	if err := database.DB.Where("project_id = ? AND user_id = ?", project.ID, user.ID).First(&member).Error; err != nil {
+		member = models.ProjectMember{ProjectID: project.ID, UserID: user.ID}
+		status = http.StatusCreated
+		eventType = models.ProjectMemberAdded
+	}
+	changed := member.Role != req.Role
+	member.Role = req.Role
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&member).Error; err != nil {
+			return err
+		}
+		if !changed {
+			return nil
+		}
+		data := webhooks.NewProjectData(project)
+		data.Member = &webhooks.MemberSnapshot{UserID: member.UserID, Role: member.Role}
+		return webhooks.Record(tx, eventType, userID, data)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save project member"})
+		return
+	}
+	webhooks.Wake()
+
+	c.JSON(status, gin.H{
+		"message": "Project member saved successfully",
+		"member": gin.H{
+			"user_id":    user.ID,
+			"name":       user.Name,
+			"email":      user.Email,
+			"role":       member.Role,
+			"created_at": member.CreatedAt,
+		},
+	})
+}
+
+// GetProjectMembers lists the members of a project visible to the caller
+func GetProjectMembers(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	projectID := c.Param("id")
+
+	var project models.Project
+	if err := database.DB.Preload("Owner").Where("id = ?", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if _, ok := projectRole(project, userID); !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	var members []models.ProjectMember
+	if err := database.DB.Preload("User").Where("project_id = ? AND user_id != ?", project.ID, project.OwnerID).Order("created_at").Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project members"})
+		return
+	}
+
+	// The owner is always listed first, whether or not a member row exists
+	memberList := []gin.H{{
+		"user_id":    project.OwnerID,
+		"name":       project.Owner.Name,
+		"email":      project.Owner.Email,
+		"role":       models.RoleOwner,
+		"created_at": project.CreatedAt,
+	}}
+	for _, member := range members {
+		memberList = append(memberList, gin.H{
+			"user_id":    member.UserID,
+			"name":       member.User.Name,
+			"email":      member.User.Email,
+			"role":       member.Role,
+			"created_at": member.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"members": memberList,
+	})
+}
+
+// RemoveProjectMember removes a member from a project. Owners can remove anyone,
+// other members can only remove themselves.
+func RemoveProjectMember(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	projectID := c.Param("id")
+
+	var project models.Project
+	if err := database.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	role, ok := projectRole(project, userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	var member models.ProjectMember
+	if err := database.DB.Where("project_id = ? AND user_id = ?", project.ID, c.Param("user_id")).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project member not found"})
+		return
+	}
+
+	if member.UserID == project.OwnerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The project owner cannot be removed"})
+		return
+	}
+	if role != models.RoleOwner && member.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can remove other members"})
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&member).Error; err != nil {
+			return err
+		}
+		data := webhooks.NewProjectData(project)
+		data.Member = &webhooks.MemberSnapshot{UserID: member.UserID, Role: member.Role}
+		return webhooks.Record(tx, models.ProjectMemberRemoved, userID, data)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove project member"})
+		return
+	}
+	webhooks.Wake()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Project member removed successfully",
+	})
+}
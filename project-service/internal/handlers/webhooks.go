@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"task-management-pkg/pagination"
+	"task-management-project-service/internal/database"
+	"task-management-project-service/internal/models"
+	"task-management-project-service/internal/webhooks"
+	"time"
+)
+
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// UpdateWebhookRequest changes the fields that are set
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url"`
+	Secret *string   `json:"secret"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
+
+// webhookDeliverySortColumns are the fields a webhook's delivery log can be sorted by
+var webhookDeliverySortColumns = map[string]string{
+	"id":              "webhook_deliveries.id",
+	"created_at":      "webhook_deliveries.created_at",
+	"next_attempt_at": "webhook_deliveries.next_attempt_at",
+	"attempts":        "webhook_deliveries.attempts",
+}
+
+// validateWebhookURL only accepts absolute http(s) URLs whose host isn't a
+// local name or non-public IP address. Hostnames are checked again for every
+// delivery, once resolved.
+func validateWebhookURL(rawURL string) bool {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(strings.TrimSuffix(target.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return webhooks.PublicAddress(addr)
+	}
+	return true
+}
+
+// validateWebhookEvents checks an event filter and removes duplicates
+func validateWebhookEvents(events []string) (models.EventFilter, bool) {
+	filter := models.EventFilter{}
+	for _, event := range events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return nil, false
+		}
+		if !slices.Contains(filter, event) {
+			filter = append(filter, event)
+		}
+	}
+	return filter, true
+}
+
+// findWebhookProject loads a project whose webhooks the user manages. Only
+// the owner does, as webhooks send project data outside the system.
+func findWebhookProject(c *gin.Context) (models.Project, bool) {
+	var project models.Project
+	if err := database.DB.Where("id = ? AND owner_id = ?", c.Param("id"), c.GetUint("user_id")).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or access denied"})
+		return project, false
+	}
+	return project, true
+}
+
+// findWebhook loads one of the project's webhooks
+func findWebhook(c *gin.Context, project models.Project) (models.Webhook, bool) {
+	var hook models.Webhook
+	if err := database.DB.Where("id = ? AND project_id = ?", c.Param("webhook_id"), project.ID).First(&hook).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return hook, false
+	}
+	return hook, true
+}
+
+// CreateWebhook subscribes a URL to the project's events. Without a secret
+// one is generated; either way it is only returned here.
+func CreateWebhook(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validateWebhookURL(req.URL) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url must be an absolute http or https URL on a public address"})
+		return
+	}
+	events, ok := validateWebhookEvents(req.Events)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event type. Use: " + strings.Join(models.WebhookEvents, ", ")})
+		return
+	}
+
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		secret = webhooks.NewSecret()
+	}
+
+	hook := models.Webhook{
+		ProjectID: project.ID,
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    events,
+		Active:    true,
+		CreatorID: userID,
+	}
+	if err := database.DB.Create(&hook).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Webhook created successfully",
+		"webhook": hook,
+		"secret":  secret,
+	})
+}
+
+// GetWebhooks lists the project's webhooks
+func GetWebhooks(c *gin.Context) {
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+
+	var hooks []models.Webhook
+	if err := database.DB.Where("project_id = ?", project.ID).Order("id").Find(&hooks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhooks": hooks,
+	})
+}
+
+// GetWebhook shows one webhook
+func GetWebhook(c *gin.Context) {
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+	hook, ok := findWebhook(c, project)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"webhook": hook,
+	})
+}
+
+// UpdateWebhook changes a webhook's URL, secret, event filter or whether
+// it is active. Disabled webhooks are sent nothing.
+func UpdateWebhook(c *gin.Context) {
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.URL != nil {
+		if !validateWebhookURL(*req.URL) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "url must be an absolute http or https URL on a public address"})
+			return
+		}
+		updates["url"] = *req.URL
+	}
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "secret cannot be empty"})
+			return
+		}
+		updates["secret"] = *req.Secret
+	}
+	if req.Events != nil {
+		events, ok := validateWebhookEvents(*req.Events)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event type. Use: " + strings.Join(models.WebhookEvents, ", ")})
+			return
+		}
+		updates["events"] = events
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+	hook, ok := findWebhook(c, project)
+	if !ok {
+		return
+	}
+
+	if len(updates) > 0 {
+		if err := database.DB.Model(&hook).Updates(updates).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+			return
+		}
+	}
+
+	database.DB.First(&hook, hook.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook updated successfully",
+		"webhook": hook,
+	})
+}
+
+// DeleteWebhook removes a webhook along with its delivery log
+func DeleteWebhook(c *gin.Context) {
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+	hook, ok := findWebhook(c, project)
+	if !ok {
+		return
+	}
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", hook.ID).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&hook).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+// GetWebhookDeliveries lists a webhook's delivery log, newest first,
+// filtered by ?status= and ?event_type=
+func GetWebhookDeliveries(c *gin.Context) {
+	page, err := pagination.Parse(c, "webhook_deliveries", webhookDeliverySortColumns, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+	hook, ok := findWebhook(c, project)
+	if !ok {
+		return
+	}
+
+	query := database.DB.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", hook.ID)
+	if status := c.Query("status"); status != "" {
+		if status != models.WebhookDeliveryPending && status != models.WebhookDeliverySucceeded && status != models.WebhookDeliveryFailed {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: pending, succeeded, failed"})
+			return
+		}
+		query = query.Where("status = ?", status)
+	}
+	if eventType := c.Query("event_type"); eventType != "" {
+		query = query.Where("event_type = ?", eventType)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := page.Apply(query).Find(&deliveries).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	hasMore := len(deliveries) > page.Limit
+	if hasMore {
+		deliveries = deliveries[:page.Limit]
+	}
+
+	var lastID uint
+	if len(deliveries) > 0 {
+		lastID = deliveries[len(deliveries)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries":  deliveries,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": pagination.NextCursor(hasMore, lastID),
+	})
+}
+
+// GetWebhookDelivery shows one delivery with the payload sent
+func GetWebhookDelivery(c *gin.Context) {
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+	hook, ok := findWebhook(c, project)
+	if !ok {
+		return
+	}
+
+	var delivery models.WebhookDelivery
+	if err := database.DB.Where("id = ? AND webhook_id = ?", c.Param("delivery_id"), hook.ID).First(&delivery).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"delivery": delivery,
+		"payload":  delivery.Payload,
+	})
+}
+
+// RedeliverWebhookDelivery sends a delivery again with a fresh set of
+// attempts. Its payload is unchanged, so receivers can recognize it by the
+// event ID.
+func RedeliverWebhookDelivery(c *gin.Context) {
+	project, ok := findWebhookProject(c)
+	if !ok {
+		return
+	}
+	hook, ok := findWebhook(c, project)
+	if !ok {
+		return
+	}
+	if !hook.Active {
+		c.JSON(http.StatusConflict, gin.H{"error": "Enable the webhook before redelivering"})
+		return
+	}
+
+	var delivery models.WebhookDelivery
+	if err := database.DB.Where("id = ? AND webhook_id = ?", c.Param("delivery_id"), hook.ID).First(&delivery).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+
+	// A pending delivery is already queued, and may be being sent right now
+	result := database.DB.Model(&delivery).Where("status <> ?", models.WebhookDeliveryPending).Updates(map[string]interface{}{
+		"status":          models.WebhookDeliveryPending,
+		"attempts":        0,
+		"next_attempt_at": time.Now(),
+	})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to redeliver"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Delivery is already pending"})
+		return
+	}
+
+	webhooks.Wake()
+
+	database.DB.First(&delivery, delivery.ID)
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Delivery queued for redelivery",
+		"delivery": delivery,
+	})
+}
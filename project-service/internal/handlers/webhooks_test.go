@@ -0,0 +1,29 @@
+package handlers
+
+import "testing"
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://ci.example.com/hooks/tasks", true},
+		{"http://93.184.216.34:8080/hook", true},
+		{"ftp://example.com/hook", false},
+		{"/hooks/tasks", false},
+		{"https://", false},
+		{"http://localhost:8083/events", false},
+		{"http://LOCALHOST./events", false},
+		{"http://api.localhost/events", false},
+		{"http://127.0.0.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data/", false},
+		{"http://10.0.0.7:5432/", false},
+		{"http://[::1]/hook", false},
+		{"http://[::ffff:192.168.0.1]/hook", false},
+	}
+	for _, test := range tests {
+		if got := validateWebhookURL(test.url); got != test.valid {
+			t.Errorf("validateWebhookURL(%q) = %v, want %v", test.url, got, test.valid)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"task-management-project-service/internal/database"
+	"task-management-project-service/internal/models"
+	"task-management-project-service/internal/webhooks"
+)
+
+// errValuesInUse is returned when tasks still use values a new workflow removes
+var errValuesInUse = errors.New("workflow values in use")
+
+// valuesInUse lists the values of a task column in a project that a new
+// workflow list no longer contains
+func valuesInUse(tx *gorm.DB, projectID uint, column string, allowed []string) ([]string, error) {
+	query := tx.Model(&models.Task{}).Where("project_id = ? AND "+column+" <> ''", projectID)
+	if len(allowed) > 0 {
+		query = query.Where(column+" NOT IN ?", allowed)
+	}
+
+	var values []string
+	err := query.Distinct(column).Pluck(column, &values).Error
+	return values, err
+}
+
+// GetProjectWorkflow returns a project's statuses, transitions and scales
+func GetProjectWorkflow(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var project models.Project
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if _, ok := projectRole(project, userID); !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project_id": project.ID,
+		"workflow":   project.Workflow,
+	})
+}
+
+// UpdateProjectWorkflow replaces a project's workflow. Only the owner may
+// change it, and values still used by the project's tasks can't be removed.
+func UpdateProjectWorkflow(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var workflow models.Workflow
+	if err := c.ShouldBindJSON(&workflow); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := workflow.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var project models.Project
+	if err := database.DB.Where("id = ?", c.Param("id")).First(&project).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	role, ok := projectRole(project, userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if role != models.RoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the project owner can change its workflow"})
+		return
+	}
+
+	inUse := gin.H{}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// Task writes lock the project row too (FOR SHARE) and check their
+		// values against its workflow, so with the row locked no task can
+		// take up a removed value before this commits
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&project, project.ID).Error; err != nil {
+			return err
+		}
+		for column, allowed := range map[string][]string{"status": workflow.Statuses, "priority": workflow.Priorities, "estimate": workflow.Estimates} {
+			values, err := valuesInUse(tx, project.ID, column, allowed)
+			if err != nil {
+				return err
+			}
+			if len(values) > 0 {
+				inUse[column] = values
+			}
+		}
+		if len(inUse) > 0 {
+			return errValuesInUse
+		}
+
+		if err := tx.Model(&project).Update("workflow", workflow).Error; err != nil {
+			return err
+		}
+		project.Workflow = workflow
+		return webhooks.Record(tx, models.ProjectWorkflowUpdated, userID, webhooks.NewProjectData(project))
+	})
+	if errors.Is(err, errValuesInUse) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "Tasks still use values this workflow removes. Move them first.",
+			"in_use": inUse,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workflow"})
+		return
+	}
+	webhooks.Wake()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Workflow updated successfully",
+		"project_id": project.ID,
+		"workflow":   workflow,
+	})
+}
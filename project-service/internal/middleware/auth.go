@@ -5,11 +5,26 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"task-management-project-service/pkg/utils"
+	"task-management-pkg/identity"
+	"task-management-pkg/jwks"
+	"task-management-project-service/internal/database"
+	"task-management-project-service/internal/models"
 )
 
 func RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Behind the gateway, trust the identity it already verified
+		if identity.TrustGateway() {
+			claims, err := identity.Claims(c.GetHeader)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid gateway identity"})
+				c.Abort()
+				return
+			}
+			setIdentity(c, claims)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
@@ -23,23 +38,79 @@ func RequireAuth() gin.HandlerFunc {
 			return
 		}
 		token := parts[1]
-		claims, err := utils.ValidateJWT(token)
+		claims, err := jwks.Validate(token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 			c.Abort()
 			return
 		}
 
-		if userIDFloat, ok := claims["user_id"].(float64); ok {
-			c.Set("user_id", uint(userIDFloat))
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
-			c.Abort()
-			return
-		}
+		setIdentity(c, claims)
+	}
+}
 
-		c.Set("email", claims["email"])
+// setIdentity rejects revoked tokens and exposes the caller's identity to handlers
+func setIdentity(c *gin.Context, claims map[string]interface{}) {
+	// Reject access tokens revoked through logout
+	if jti, ok := claims["jti"].(string); ok && isRevoked(jti) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+		c.Abort()
+		return
+	}
 
-		c.Next()
+	userIDFloat, ok := claims["user_id"].(float64)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID in token"})
+		c.Abort()
+		return
 	}
+	// Deactivating a user ends their sessions, including unexpired access tokens
+	if isDeactivated(uint(userIDFloat)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account has been deactivated"})
+		c.Abort()
+		return
+	}
+	c.Set("user_id", uint(userIDFloat))
+
+	c.Set("email", claims["email"])
+
+	// Tokens issued before roles were added to the claims are plain users
+	role, _ := claims["role"].(string)
+	if role == "" {
+		role = "user"
+	}
+	c.Set("role", role)
+	c.Next()
+}
+
+// RequireRole only lets through users whose token carries one of the given roles.
+// It must run after RequireAuth.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+	}
+}
+
+// isRevoked checks the shared denylist for a token ID
+func isRevoked(jti string) bool {
+	var count int64
+	database.DB.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count)
+	return count > 0
+}
+
+// isDeactivated checks whether an admin deactivated the user. The column
+// belongs to auth-service, which migrates the shared users table.
+func isDeactivated(userID uint) bool {
+	var count int64
+	database.DB.Table("users").Where("id = ? AND deactivated_at IS NOT NULL", userID).Count(&count)
+	return count > 0
 }
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"task-management-project-service/internal/database"
+)
+
+// RequireVerifiedEmail only lets through users who verified their email
+// address, when REQUIRE_VERIFIED_EMAIL is "true"; otherwise everyone is.
+// The auth service records verification in the shared users table. It
+// must run after RequireAuth.
+func RequireVerifiedEmail() gin.HandlerFunc {
+	required := os.Getenv("REQUIRE_VERIFIED_EMAIL") == "true"
+	if required {
+		log.Println("Unverified users cannot create projects (REQUIRE_VERIFIED_EMAIL)")
+	}
+
+	return func(c *gin.Context) {
+		if !required {
+			c.Next()
+			return
+		}
+
+		var verified bool
+		err := database.DB.Raw("SELECT email_verified_at IS NOT NULL FROM users WHERE id = ? AND deleted_at IS NULL", c.GetUint("user_id")).
+			Scan(&verified).Error
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check email verification"})
+			c.Abort()
+			return
+		}
+		if !verified {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Verify your email address first"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
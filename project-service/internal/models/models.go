@@ -1,7 +1,12 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"gorm.io/gorm"
+	"strings"
 	"time"
 )
 
@@ -22,6 +27,7 @@ type Project struct {
 	Description string         `json:"description"`
 	OwnerID     uint           `json:"owner_id" gorm:"not null"`
 	Owner       *User          `json:"owner" gorm:"foreignKey:OwnerID"`
+	Workflow    Workflow       `json:"workflow" gorm:"type:jsonb"`
 	Tasks       []Task         `json:"tasks,omitempty" gorm:"foreignKey:ProjectID"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -46,3 +52,278 @@ type Task struct {
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
 }
+
+// Project member roles, from most to least privileged
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type ProjectMember struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"not null;uniqueIndex:idx_project_member"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_project_member"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Role      string    `json:"role" gorm:"not null;default:viewer"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RevokedToken is the shared denylist of access token IDs (jti claims),
+// written by auth-service on logout
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Workflow is a project's task lifecycle: the statuses a task moves through,
+// which moves are allowed, and the priority and estimate scales. Lists are
+// ordered; sorting by status, priority or estimate follows that order.
+type Workflow struct {
+	// Statuses lists every status; new tasks start in the first one
+	Statuses []string `json:"statuses"`
+	// DoneStatus is the status that counts as finished
+	DoneStatus string `json:"done_status"`
+	// Transitions maps a status to the statuses it may move to. A status
+	// without an entry may move to any status.
+	Transitions map[string][]string `json:"transitions,omitempty"`
+	Priorities  []string            `json:"priorities"`
+	Estimates   []string            `json:"estimates"`
+}
+
+// DefaultWorkflow is the workflow new projects start with
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses:   []string{"Not Started", "In Progress", "Blocked", "Done"},
+		DoneStatus: "Done",
+		Priorities: []string{"Low", "Medium", "High", "Urgent"},
+		Estimates:  []string{"S", "M", "L", "XL"},
+	}
+}
+
+// Value stores the workflow as JSON; an empty workflow is stored as the default
+func (w Workflow) Value() (driver.Value, error) {
+	if len(w.Statuses) == 0 {
+		w = DefaultWorkflow()
+	}
+	data, err := json.Marshal(w)
+	return string(data), err
+}
+
+// Scan reads a stored workflow; projects without one get the default
+func (w *Workflow) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = DefaultWorkflow()
+		return nil
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	default:
+		return errors.New("unsupported type for Workflow")
+	}
+}
+
+// Validate checks the workflow is self-consistent
+func (w Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return errors.New("workflow needs at least one status")
+	}
+	for name, values := range map[string][]string{"status": w.Statuses, "priority": w.Priorities, "estimate": w.Estimates} {
+		seen := map[string]bool{}
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("%s names cannot be empty", name)
+			}
+			if seen[value] {
+				return fmt.Errorf("duplicate %s %q", name, value)
+			}
+			seen[value] = true
+		}
+	}
+	if !w.HasStatus(w.DoneStatus) {
+		return fmt.Errorf("done_status %q is not one of the statuses", w.DoneStatus)
+	}
+	for from, targets := range w.Transitions {
+		if !w.HasStatus(from) {
+			return fmt.Errorf("transition from unknown status %q", from)
+		}
+		for _, to := range targets {
+			if !w.HasStatus(to) {
+				return fmt.Errorf("transition from %q to unknown status %q", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+func (w Workflow) InitialStatus() string {
+	return w.Statuses[0]
+}
+
+func (w Workflow) HasStatus(status string) bool {
+	return contains(w.Statuses, status)
+}
+
+func (w Workflow) HasPriority(priority string) bool {
+	return contains(w.Priorities, priority)
+}
+
+func (w Workflow) HasEstimate(estimate string) bool {
+	return contains(w.Estimates, estimate)
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Staying in the same status is always allowed.
+func (w Workflow) CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	targets, restricted := w.Transitions[from]
+	return !restricted || contains(targets, to)
+}
+
+// NextStatuses lists the statuses a task may move to from the given one
+func (w Workflow) NextStatuses(from string) []string {
+	var next []string
+	for _, status := range w.Statuses {
+		if status != from && w.CanTransition(from, status) {
+			next = append(next, status)
+		}
+	}
+	return next
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook event types. Task events are published by task-service; project
+// events are recorded here, in the transaction that makes the change.
+const (
+	TaskCreated            = "task.created"
+	TaskUpdated            = "task.updated"
+	TaskDeleted            = "task.deleted"
+	ProjectUpdated         = "project.updated"
+	ProjectWorkflowUpdated = "project.workflow_updated"
+	ProjectMemberAdded     = "project.member_added"
+	ProjectMemberUpdated   = "project.member_updated"
+	ProjectMemberRemoved   = "project.member_removed"
+	ProjectDeleted         = "project.deleted"
+)
+
+// WebhookEvents lists every event type a webhook can subscribe to
+var WebhookEvents = []string{
+	TaskCreated, TaskUpdated, TaskDeleted,
+	ProjectUpdated, ProjectWorkflowUpdated, ProjectMemberAdded, ProjectMemberUpdated, ProjectMemberRemoved, ProjectDeleted,
+}
+
+// TaskEvent is a task change published by task-service. ID is chosen by the
+// publisher and makes redelivered events harmless; ActorID is 0 for changes
+// no user made, such as scheduled ones.
+type TaskEvent struct {
+	ID         string        `json:"id" binding:"required"`
+	Type       string        `json:"type" binding:"required"`
+	ActorID    uint          `json:"actor_id"`
+	Task       TaskSnapshot  `json:"task" binding:"required"`
+	Changes    []FieldChange `json:"changes"`
+	OccurredAt time.Time     `json:"occurred_at"`
+}
+
+// TaskSnapshot is the state of a task after the event (before it, for
+// task.deleted)
+type TaskSnapshot struct {
+	ID          uint       `json:"id" binding:"required"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   uint       `json:"project_id" binding:"required"`
+	AssigneeID  *uint      `json:"assignee_id"`
+	CreatorID   *uint      `json:"creator_id"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	Estimate    string     `json:"estimate"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+// FieldChange is a field's value before and after an event. Values are
+// rendered as strings; empty means unset.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// EventFilter is the event types a webhook subscribes to; empty means all
+type EventFilter []string
+
+// Value stores the filter as a JSON array
+func (f EventFilter) Value() (driver.Value, error) {
+	if f == nil {
+		f = EventFilter{}
+	}
+	data, err := json.Marshal(f)
+	return string(data), err
+}
+
+// Scan reads a stored filter
+func (f *EventFilter) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*f = EventFilter{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	default:
+		return errors.New("unsupported type for EventFilter")
+	}
+}
+
+// Webhook subscribes a URL to a project's events. Payloads are signed with
+// Secret, which is only shown when the webhook is created.
+type Webhook struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	ProjectID uint        `json:"project_id" gorm:"not null;index"`
+	URL       string      `json:"url" gorm:"not null"`
+	Secret    string      `json:"-" gorm:"not null"`
+	Events    EventFilter `json:"events" gorm:"type:jsonb;not null;default:'[]'"`
+	Active    bool        `json:"active" gorm:"not null;default:true"`
+	CreatorID uint        `json:"creator_id" gorm:"not null"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookDelivery is one event queued for one webhook. Payload is the exact
+// body sent, so redeliveries carry the same signature. Pending deliveries
+// are the queue; the rest are the delivery log.
+type WebhookDelivery struct {
+	ID             uint       `json:"id" gorm:"primaryKey"`
+	WebhookID      uint       `json:"webhook_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventID        string     `json:"event_id" gorm:"not null;uniqueIndex:idx_webhook_delivery_event"`
+	EventType      string     `json:"event_type" gorm:"not null"`
+	Payload        string     `json:"-" gorm:"type:text;not null"`
+	Status         string     `json:"status" gorm:"not null;default:pending;index"`
+	Attempts       int        `json:"attempts" gorm:"not null;default:0"`
+	ResponseStatus int        `json:"response_status"`
+	LastError      string     `json:"last_error"`
+	NextAttemptAt  time.Time  `json:"next_attempt_at" gorm:"not null;index"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.20.0.5", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.100.1.1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::1", false},
+		{"::", false},
+		{"fd00::1", false},
+		{"fe80::1%eth0", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+	}
+	for _, test := range tests {
+		if got := PublicAddress(netip.MustParseAddr(test.addr)); got != test.public {
+			t.Errorf("PublicAddress(%s) = %v, want %v", test.addr, got, test.public)
+		}
+	}
+}
+
+func TestWorkerRefusesLocalEndpoints(t *testing.T) {
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := NewWorker().Client.Post(server.URL, "application/json", nil)
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("Post to %s: got %v, want ErrBlockedAddress", server.URL, err)
+	}
+	if called {
+		t.Error("the local endpoint was reached")
+	}
+}
@@ -0,0 +1,135 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"task-management-project-service/internal/models"
+	"time"
+)
+
+// Payload is the JSON body POSTed to webhooks
+type Payload struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	ProjectID  uint        `json:"project_id"`
+	ActorID    uint        `json:"actor_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// TaskData is the data of task.* events
+type TaskData struct {
+	Task    models.TaskSnapshot  `json:"task"`
+	Changes []models.FieldChange `json:"changes,omitempty"`
+}
+
+// ProjectData is the data of project.* events. Member is set for member
+// events, Changes for project.updated.
+type ProjectData struct {
+	Project ProjectSnapshot      `json:"project"`
+	Changes []models.FieldChange `json:"changes,omitempty"`
+	Member  *MemberSnapshot      `json:"member,omitempty"`
+}
+
+// ProjectSnapshot is the state of a project after the event
+type ProjectSnapshot struct {
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	OwnerID     uint            `json:"owner_id"`
+	Workflow    models.Workflow `json:"workflow"`
+}
+
+// MemberSnapshot is the member a member event is about
+type MemberSnapshot struct {
+	UserID uint   `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// NewProjectData builds the data of a project event
+func NewProjectData(project models.Project) ProjectData {
+	return ProjectData{Project: ProjectSnapshot{
+		ID:          project.ID,
+		Name:        project.Name,
+		Description: project.Description,
+		OwnerID:     project.OwnerID,
+		Workflow:    project.Workflow,
+	}}
+}
+
+// Record queues a project event for the project's webhooks. Call it with
+// the transaction that makes the change, so only committed changes are sent.
+func Record(tx *gorm.DB, eventType string, actorID uint, data ProjectData) error {
+	_, err := Enqueue(tx, data.Project.ID, eventType, newEventID(), actorID, time.Now(), data)
+	return err
+}
+
+// Enqueue queues an event for every active webhook of the project that
+// subscribes to its type, and returns how many deliveries were queued. An
+// event already queued for a webhook, by ID, is not queued again.
+func Enqueue(tx *gorm.DB, projectID uint, eventType, eventID string, actorID uint, occurredAt time.Time, data interface{}) (int64, error) {
+	filter, _ := json.Marshal([]string{eventType})
+	var hooks []models.Webhook
+	err := tx.Where("project_id = ? AND active", projectID).
+		Where("events = '[]' OR events @> ?", string(filter)).
+		Find(&hooks).Error
+	if err != nil || len(hooks) == 0 {
+		return 0, err
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:         eventID,
+		Type:       eventType,
+		ProjectID:  projectID,
+		ActorID:    actorID,
+		OccurredAt: occurredAt,
+		Data:       data,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var queued int64
+	for _, hook := range hooks {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       eventID,
+			EventType:     eventType,
+			Payload:       string(body),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+		if result.Error != nil {
+			return queued, result.Error
+		}
+		queued += result.RowsAffected
+	}
+	return queued, nil
+}
+
+// Sign computes the signature header value of a payload: "sha256=" and the
+// hex HMAC-SHA256 of the body keyed with the webhook's secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret generates a signing secret for webhooks created without one
+func NewSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
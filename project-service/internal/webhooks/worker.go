@@ -0,0 +1,254 @@
+package webhooks
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"task-management-pkg/worker"
+	"task-management-project-service/internal/database"
+	"task-management-project-service/internal/models"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-ID"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature-256"
+)
+
+const (
+	// batchSize bounds how many due deliveries one run picks up
+	batchSize = 100
+	// webhookBatchSize bounds how many of them go to the same webhook, which
+	// are sent one after another
+	webhookBatchSize = 10
+	// lease is how long claimed deliveries are left to the worker that
+	// claimed them. It outlasts sending a full webhook batch, after which
+	// a crashed worker's deliveries are claimed again.
+	lease = 5 * time.Minute
+)
+
+// backoff spaces out retries: 30s after the first failure, doubling up to
+// an hour, so an endpoint that is down for a while isn't hammered
+var backoff = worker.Backoff{First: 30 * time.Second, Max: time.Hour}
+
+// loop runs the worker; writers wake it rather than waiting for the next
+// tick, and finished webhook batches wake it to send what queued meanwhile
+var loop = worker.NewLoop()
+
+// Wake asks the worker to look for due deliveries now. Call it after the
+// transaction that queued them commits.
+func Wake() {
+	loop.Wake()
+}
+
+// Worker POSTs pending deliveries, retrying failures with exponential
+// backoff until MaxAttempts is reached. Only 2xx responses count as
+// delivered; redirects are not followed. Each webhook's deliveries are sent
+// in order, and up to Concurrency webhooks are sent to at once, so a slow
+// endpoint only holds up its own deliveries.
+type Worker struct {
+	Client      *http.Client
+	MaxAttempts int
+	Concurrency int
+
+	mu sync.Mutex
+	// sending holds the webhooks being sent to
+	sending map[uint]bool
+}
+
+// NewWorker configures a worker from WEBHOOK_MAX_ATTEMPTS (default 8) and
+// WEBHOOK_CONCURRENCY (default 8)
+func NewWorker() *Worker {
+	maxAttempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = 8
+	}
+	concurrency, err := strconv.Atoi(os.Getenv("WEBHOOK_CONCURRENCY"))
+	if err != nil || concurrency < 1 {
+		concurrency = 8
+	}
+
+	// Every connection is checked where it is made, as the URL's host may
+	// resolve differently by then. Proxies are not used, as they would make
+	// the connection instead.
+	dialer := &net.Dialer{Timeout: 5 * time.Second, Control: checkDialAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   10 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	return &Worker{Client: client, MaxAttempts: maxAttempts, Concurrency: concurrency, sending: map[uint]bool{}}
+}
+
+// Start sends due deliveries every interval, or sooner when woken, in the
+// background
+func (w *Worker) Start(interval time.Duration) {
+	loop.Start(interval, func() { w.run(time.Now()) })
+}
+
+// run claims due deliveries for webhooks that aren't being sent to already
+// and sends each webhook's in the background
+func (w *Worker) run(now time.Time) {
+	w.mu.Lock()
+	// Webhook IDs start at 1, so 0 keeps the list from being empty
+	busy := []uint{0}
+	for webhookID := range w.sending {
+		busy = append(busy, webhookID)
+	}
+	free := w.Concurrency - len(w.sending)
+	w.mu.Unlock()
+	if free <= 0 {
+		return
+	}
+
+	deliveries, err := claim(now, busy, free)
+	if err != nil {
+		log.Printf("Webhook worker: failed to claim due deliveries: %v", err)
+		return
+	}
+
+	byWebhook := map[uint][]models.WebhookDelivery{}
+	for _, delivery := range deliveries {
+		byWebhook[delivery.WebhookID] = append(byWebhook[delivery.WebhookID], delivery)
+	}
+	for webhookID, batch := range byWebhook {
+		w.mu.Lock()
+		w.sending[webhookID] = true
+		w.mu.Unlock()
+
+		go func() {
+			for _, delivery := range batch {
+				if err := w.deliver(delivery); err != nil {
+					log.Printf("Webhook worker: failed to process delivery %d: %v", delivery.ID, err)
+				}
+			}
+
+			w.mu.Lock()
+			delete(w.sending, webhookID)
+			w.mu.Unlock()
+			// The webhook may have more due deliveries
+			Wake()
+		}()
+	}
+}
+
+// claim leases due deliveries to this worker and returns them, oldest first:
+// up to webhookBatchSize each for at most maxWebhooks webhooks, those
+// due longest first, and none for the busy ones. Each claim counts as an
+// attempt. It is a single statement, so nothing stays locked while sending,
+// and deliveries another worker claimed meanwhile are no longer due.
+func claim(now time.Time, busy []uint, maxWebhooks int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := database.DB.Raw(`
+		WITH due AS (
+			SELECT id, webhook_id, next_attempt_at,
+				row_number() OVER (PARTITION BY webhook_id ORDER BY next_attempt_at, id) AS position
+			FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ? AND webhook_id NOT IN ?
+		), hooks AS (
+			SELECT webhook_id FROM due GROUP BY webhook_id ORDER BY min(next_attempt_at), webhook_id LIMIT ?
+		), picked AS (
+			SELECT id FROM due
+			WHERE position <= ? AND webhook_id IN (SELECT webhook_id FROM hooks)
+			ORDER BY next_attempt_at, id LIMIT ?
+		)
+		UPDATE webhook_deliveries SET attempts = attempts + 1, next_attempt_at = ?, updated_at = ?
+		WHERE id IN (SELECT id FROM picked) AND status = ? AND next_attempt_at <= ?
+		RETURNING *`,
+		models.WebhookDeliveryPending, now, busy, maxWebhooks,
+		webhookBatchSize, batchSize,
+		now.Add(lease), now, models.WebhookDeliveryPending, now).
+		Scan(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(deliveries, func(a, b models.WebhookDelivery) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return deliveries, nil
+}
+
+// deliver sends a claimed delivery and records the outcome. The outcome is
+// dropped if the claim was lost meanwhile: the lease ran out and another
+// worker claimed the delivery again, or the webhook was disabled.
+func (w *Worker) deliver(delivery models.WebhookDelivery) error {
+	claimed := database.DB.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ? AND attempts = ?", delivery.ID, models.WebhookDeliveryPending, delivery.Attempts)
+
+	var hook models.Webhook
+	if err := database.DB.First(&hook, delivery.WebhookID).Error; err != nil {
+		return err
+	}
+	if !hook.Active {
+		return claimed.Updates(map[string]interface{}{
+			"status":     models.WebhookDeliveryFailed,
+			"last_error": "webhook is disabled",
+		}).Error
+	}
+
+	status, sendErr := w.send(hook, delivery)
+
+	updates := map[string]interface{}{
+		"response_status": status,
+	}
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.WebhookDeliverySucceeded
+		updates["delivered_at"] = time.Now()
+		updates["last_error"] = ""
+	case delivery.Attempts >= w.MaxAttempts:
+		updates["status"] = models.WebhookDeliveryFailed
+		updates["last_error"] = sendErr.Error()
+		log.Printf("❌ Webhook delivery %d failed for good after %d attempts: %v", delivery.ID, delivery.Attempts, sendErr)
+	default:
+		updates["last_error"] = sendErr.Error()
+		updates["next_attempt_at"] = time.Now().Add(backoff.Delay(delivery.Attempts))
+		log.Printf("❌ Webhook delivery %d failed (attempt %d), retrying: %v", delivery.ID, delivery.Attempts, sendErr)
+	}
+
+	return claimed.Updates(updates).Error
+}
+
+// send POSTs the signed payload, returning the response status. The
+// response body is discarded: endpoints may be anything, and what they answer
+// isn't kept or shown.
+func (w *Worker) send(hook models.Webhook, delivery models.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-management-webhooks")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderSignature, Sign(hook.Secret, body))
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
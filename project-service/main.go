@@ -3,9 +3,12 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
 	"task-management-project-service/internal/database"
 	"task-management-project-service/internal/handlers"
 	"task-management-project-service/internal/middleware"
+	"task-management-project-service/internal/webhooks"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,6 +17,9 @@ func main() {
 	// Connect to database
 	database.Connect()
 
+	// Deliver queued webhook events in the background
+	webhooks.NewWorker().Start(webhookInterval())
+
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -36,27 +42,77 @@ func main() {
 	// Health check
 	r.GET("/health", handlers.HealthCheck)
 
+	// Task event intake for task-service (not routed by the gateway)
+	r.POST("/events", middleware.RequireServiceToken(), handlers.ReceiveTaskEvent)
+
 	// Project routes (all protected)
 	projects := r.Group("/projects")
 	projects.Use(middleware.RequireAuth())
 	{
-		projects.POST("", handlers.CreateProject)
+		projects.POST("", middleware.RequireVerifiedEmail(), handlers.CreateProject)
 		projects.GET("", handlers.GetProjects)
 		projects.GET("/:id", handlers.GetProjectByID)
 		projects.PUT("/:id", handlers.UpdateProject)
 		projects.DELETE("/:id", handlers.DeleteProject)
+		projects.POST("/:id/members", handlers.AddProjectMember)
+		projects.GET("/:id/members", handlers.GetProjectMembers)
+		projects.DELETE("/:id/members/:user_id", handlers.RemoveProjectMember)
+		projects.GET("/:id/workflow", handlers.GetProjectWorkflow)
+		projects.PUT("/:id/workflow", handlers.UpdateProjectWorkflow)
+		projects.POST("/:id/webhooks", handlers.CreateWebhook)
+		projects.GET("/:id/webhooks", handlers.GetWebhooks)
+		projects.GET("/:id/webhooks/:webhook_id", handlers.GetWebhook)
+		projects.PUT("/:id/webhooks/:webhook_id", handlers.UpdateWebhook)
+		projects.DELETE("/:id/webhooks/:webhook_id", handlers.DeleteWebhook)
+		projects.GET("/:id/webhooks/:webhook_id/deliveries", handlers.GetWebhookDeliveries)
+		projects.GET("/:id/webhooks/:webhook_id/deliveries/:delivery_id", handlers.GetWebhookDelivery)
+		projects.POST("/:id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver", handlers.RedeliverWebhookDelivery)
+	}
+
+	// Admin routes (admin role only)
+	admin := r.Group("/admin")
+	admin.Use(middleware.RequireAuth(), middleware.RequireRole("admin"))
+	{
+		admin.GET("/projects", handlers.AdminGetProjects)
+		admin.GET("/projects/:id", handlers.AdminGetProjectByID)
 	}
 
 	log.Println("🗂️  Project Service starting on port 8083")
 	log.Println("📋 Available endpoints:")
 	log.Println("   GET  /health")
+	log.Println("   POST /events (internal)")
 	log.Println("   POST /projects")
 	log.Println("   GET  /projects")
 	log.Println("   GET  /projects/:id")
 	log.Println("   PUT  /projects/:id")
 	log.Println("   DELETE /projects/:id")
+	log.Println("   POST /projects/:id/members")
+	log.Println("   GET  /projects/:id/members")
+	log.Println("   DELETE /projects/:id/members/:user_id")
+	log.Println("   GET  /projects/:id/workflow")
+	log.Println("   PUT  /projects/:id/workflow")
+	log.Println("   POST /projects/:id/webhooks")
+	log.Println("   GET  /projects/:id/webhooks")
+	log.Println("   GET  /projects/:id/webhooks/:webhook_id")
+	log.Println("   PUT  /projects/:id/webhooks/:webhook_id")
+	log.Println("   DELETE /projects/:id/webhooks/:webhook_id")
+	log.Println("   GET  /projects/:id/webhooks/:webhook_id/deliveries")
+	log.Println("   GET  /projects/:id/webhooks/:webhook_id/deliveries/:delivery_id")
+	log.Println("   POST /projects/:id/webhooks/:webhook_id/deliveries/:delivery_id/redeliver")
+	log.Println("   GET  /admin/projects")
+	log.Println("   GET  /admin/projects/:id")
 
 	if err := r.Run(":8083"); err != nil {
 		log.Fatal("Failed to start project service:", err)
 	}
 }
+
+// webhookInterval reads how often the webhook queue is polled from
+// WEBHOOK_INTERVAL (e.g. 10s), defaulting to every 10 seconds. New events
+// are sent right away either way.
+func webhookInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("WEBHOOK_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return 10 * time.Second
+}
@@ -30,7 +30,20 @@ func Connect() {
 	}
 
 	// Ensure our tables exist (should already be there from monolith)
-	err = DB.AutoMigrate(&models.User{}, &models.Project{}, &models.Task{})
+	err = DB.AutoMigrate(&models.User{}, &models.Project{}, &models.Task{}, &models.ProjectMember{}, &models.RevokedToken{}, &models.TaskEvent{}, &models.TaskDependency{}, &models.Comment{}, &models.Notification{})
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Projects created before workflows existed get the default one
+	err = DB.Model(&models.Project{}).Where("workflow IS NULL").UpdateColumn("workflow", models.DefaultWorkflow()).Error
+	if err != nil {
+		log.Fatal("Failed to migrate database:", err)
+	}
+
+	// Events recorded before they carried a project get their task's
+	err = DB.Exec("UPDATE task_events SET project_id = tasks.project_id FROM tasks " +
+		"WHERE tasks.id = task_events.task_id AND task_events.project_id IS NULL").Error
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"gorm.io/gorm"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+)
+
+// accessibleProjectIDs builds a subquery of project IDs the user owns or is a member of
+func accessibleProjectIDs(userID uint) *gorm.DB {
+	memberProjects := database.DB.Model(&models.ProjectMember{}).Select("project_id").Where("user_id = ?", userID)
+	return database.DB.Model(&models.Project{}).Select("id").Where("owner_id = ? OR id IN (?)", userID, memberProjects)
+}
+
+// projectRole returns the user's role on a project. The project owner is
+// always treated as an owner, even without a member row.
+func projectRole(project models.Project, userID uint) (string, bool) {
+	if project.OwnerID == userID {
+		return models.RoleOwner, true
+	}
+
+	var member models.ProjectMember
+	if err := database.DB.Where("project_id = ? AND user_id = ?", project.ID, userID).First(&member).Error; err != nil {
+		return "", false
+	}
+	return member.Role, true
+}
+
+// findProjectForRole loads a project and checks the user can access it.
+// When edit is true, viewers are rejected as well.
+func findProjectForRole(projectID interface{}, userID uint, edit bool) (models.Project, bool) {
+	var project models.Project
+	if err := database.DB.Where("id = ?", projectID).First(&project).Error; err != nil {
+		return project, false
+	}
+
+	role, ok := projectRole(project, userID)
+	if !ok || (edit && !canEdit(role)) {
+		return project, false
+	}
+	return project, true
+}
+
+func canEdit(role string) bool {
+	return role == models.RoleOwner || role == models.RoleEditor
+}
+
+// findTaskForUser loads a task with its project and returns the user's role
+// on that project, with the same checks as GetTaskByID
+func findTaskForUser(taskID interface{}, userID uint) (models.Task, string, bool) {
+	var task models.Task
+	if err := database.DB.Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
+		return task, "", false
+	}
+
+	role, ok := projectRole(task.Project, userID)
+	return task, role, ok
+}
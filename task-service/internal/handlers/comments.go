@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"regexp"
+	"strings"
+	"task-management-pkg/pagination"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+)
+
+type CommentRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// mentionPattern matches @email mentions, e.g. "thanks @ana@example.com!"
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,})`)
+
+// commentSortColumns are the fields GET /tasks/:id/comments can be sorted by
+var commentSortColumns = map[string]string{
+	"id":         "comments.id",
+	"created_at": "comments.created_at",
+}
+
+// resolveMentions finds the users mentioned in a comment body. Only users who
+// can access the task's project are resolved; other addresses stay plain text.
+func resolveMentions(body string, project models.Project) ([]models.User, error) {
+	seen := map[string]bool{}
+	var emails []string
+	for _, match := range mentionPattern.FindAllStringSubmatch(body, -1) {
+		email := strings.ToLower(strings.TrimRight(match[1], "."))
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	memberIDs := database.DB.Model(&models.ProjectMember{}).Select("user_id").Where("project_id = ?", project.ID)
+	var users []models.User
+	err := database.DB.
+		Where("LOWER(email) IN ?", emails).
+		Where("id = ? OR id IN (?)", project.OwnerID, memberIDs).
+		Find(&users).Error
+	return users, err
+}
+
+// notifyMentions notifies mentioned users, skipping the author and anyone
+// in alreadyNotified
+func notifyMentions(tx *gorm.DB, comment models.Comment, task models.Task, author models.User, alreadyNotified map[uint]bool) error {
+	for _, user := range comment.Mentions {
+		if user.ID == author.ID || alreadyNotified[user.ID] {
+			continue
+		}
+		notification := models.Notification{
+			UserID:    user.ID,
+			Type:      models.NotificationMention,
+			ActorID:   &author.ID,
+			TaskID:    &task.ID,
+			CommentID: &comment.ID,
+			Message:   fmt.Sprintf("%s mentioned you on \"%s\"", author.Name, task.Title),
+		}
+		if err := tx.Create(&notification).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func commentResponse(comment models.Comment) gin.H {
+	mentions := make([]gin.H, 0, len(comment.Mentions))
+	for _, user := range comment.Mentions {
+		mentions = append(mentions, gin.H{"id": user.ID, "name": user.Name, "email": user.Email})
+	}
+
+	return gin.H{
+		"id":      comment.ID,
+		"task_id": comment.TaskID,
+		"author": gin.H{
+			"id":   comment.AuthorID,
+			"name": userName(comment.Author),
+		},
+		"body":       comment.Body,
+		"mentions":   mentions,
+		"created_at": comment.CreatedAt,
+		"updated_at": comment.UpdatedAt,
+	}
+}
+
+// CreateComment handles commenting on a task and notifies mentioned users
+func CreateComment(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body cannot be empty"})
+		return
+	}
+
+	task, _, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	var author models.User
+	if err := database.DB.First(&author, userID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+		return
+	}
+
+	mentions, err := resolveMentions(req.Body, task.Project)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve mentions"})
+		return
+	}
+
+	comment := models.Comment{
+		TaskID:   task.ID,
+		AuthorID: userID,
+		Author:   &author,
+		Body:     req.Body,
+		Mentions: mentions,
+	}
+
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Author", "Mentions.*").Create(&comment).Error; err != nil {
+			return err
+		}
+		return notifyMentions(tx, comment, task, author, nil)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Comment created successfully",
+		"comment": commentResponse(comment),
+	})
+}
+
+// GetComments lists a task's comments, oldest first
+func GetComments(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	page, err := pagination.Parse(c, "comments", commentSortColumns, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, _, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	query := database.DB.Model(&models.Comment{}).Where("task_id = ?", task.ID).Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	var comments []models.Comment
+	if err := page.Apply(query.Preload("Author").Preload("Mentions")).Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	hasMore := len(comments) > page.Limit
+	if hasMore {
+		comments = comments[:page.Limit]
+	}
+
+	commentList := make([]gin.H, 0, len(comments))
+	for _, comment := range comments {
+		commentList = append(commentList, commentResponse(comment))
+	}
+
+	var lastID uint
+	if len(comments) > 0 {
+		lastID = comments[len(comments)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"comments":    commentList,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": pagination.NextCursor(hasMore, lastID),
+	})
+}
+
+// findComment loads a comment on a task the user can access
+func findComment(c *gin.Context, userID uint) (models.Comment, models.Task, string, bool) {
+	task, role, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return models.Comment{}, task, "", false
+	}
+
+	var comment models.Comment
+	if err := database.DB.Preload("Author").Preload("Mentions").Where("id = ? AND task_id = ?", c.Param("comment_id"), task.ID).First(&comment).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return comment, task, "", false
+	}
+	return comment, task, role, true
+}
+
+// UpdateComment handles editing a comment; only its author may edit it.
+// Users newly mentioned by the edit are notified.
+func UpdateComment(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req CommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Body) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Comment body cannot be empty"})
+		return
+	}
+
+	comment, task, _, ok := findComment(c, userID)
+	if !ok {
+		return
+	}
+	if comment.AuthorID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author can edit a comment"})
+		return
+	}
+
+	mentions, err := resolveMentions(req.Body, task.Project)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve mentions"})
+		return
+	}
+
+	alreadyNotified := map[uint]bool{}
+	for _, user := range comment.Mentions {
+		alreadyNotified[user.ID] = true
+	}
+
+	comment.Body = req.Body
+	err = database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&comment).Update("body", comment.Body).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&comment).Omit("Mentions.*").Association("Mentions").Replace(mentions); err != nil {
+			return err
+		}
+		comment.Mentions = mentions
+		return notifyMentions(tx, comment, task, *comment.Author, alreadyNotified)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment updated successfully",
+		"comment": commentResponse(comment),
+	})
+}
+
+// DeleteComment handles deleting a comment; its author or the project owner may delete it
+func DeleteComment(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	comment, _, role, ok := findComment(c, userID)
+	if !ok {
+		return
+	}
+	if comment.AuthorID != userID && role != models.RoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author or the project owner can delete a comment"})
+		return
+	}
+
+	if err := database.DB.Delete(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Comment deleted successfully",
+	})
+}
@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"hash/fnv"
+	"net/http"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+)
+
+type ParentRequest struct {
+	ParentID *uint `json:"parent_id"`
+}
+
+type DependencyRequest struct {
+	BlockedByID *uint `json:"blocked_by_id"`
+	BlocksID    *uint `json:"blocks_id"`
+}
+
+// dependencyLock serializes dependency and parent changes so concurrent
+// requests can't close a cycle between them
+var dependencyLock = advisoryLockKey("task_dependencies")
+
+var errCycle = errors.New("dependency cycle")
+
+// advisoryLockKey derives a PostgreSQL advisory lock key from a name, so
+// keys read as what they lock and don't need picking by hand
+func advisoryLockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	return int64(hash.Sum64())
+}
+
+// notDone matches tasks that aren't in their own project's done status
+const notDone = "tasks.status <> (SELECT projects.workflow->>'done_status' FROM projects WHERE projects.id = tasks.project_id)"
+
+// createsParentCycle reports whether making parentID the parent of taskID
+// would make a task its own ancestor
+func createsParentCycle(tx *gorm.DB, taskID, parentID uint) (bool, error) {
+	var found bool
+	err := tx.Raw(`
+		WITH RECURSIVE ancestors(id, parent_id) AS (
+			SELECT id, parent_id FROM tasks WHERE id = ?
+			UNION
+			SELECT t.id, t.parent_id FROM tasks t JOIN ancestors a ON t.id = a.parent_id
+		)
+		SELECT EXISTS (SELECT 1 FROM ancestors WHERE id = ?)`, parentID, taskID).Scan(&found).Error
+	return found, err
+}
+
+// createsDependencyCycle reports whether letting blockerID block blockedID
+// would close a loop, i.e. they are the same task or blockedID already blocks
+// blockerID transitively
+func createsDependencyCycle(tx *gorm.DB, blockerID, blockedID uint) (bool, error) {
+	if blockerID == blockedID {
+		return true, nil
+	}
+	var found bool
+	err := tx.Raw(`
+		WITH RECURSIVE downstream(id) AS (
+			SELECT blocked_id FROM task_dependencies WHERE blocker_id = ?
+			UNION
+			SELECT d.blocked_id FROM task_dependencies d JOIN downstream ON d.blocker_id = downstream.id
+		)
+		SELECT EXISTS (SELECT 1 FROM downstream WHERE id = ?)`, blockedID, blockerID).Scan(&found).Error
+	return found, err
+}
+
+// unfinishedPrerequisites lists the tasks that must be finished before the task
+// can be: its blockers and its subtasks
+func unfinishedPrerequisites(taskID uint) (blockers []uint, subtasks []uint, err error) {
+	blockerIDs := database.DB.Model(&models.TaskDependency{}).Select("blocker_id").Where("blocked_id = ?", taskID)
+	err = database.DB.Model(&models.Task{}).Where("id IN (?)", blockerIDs).Where(notDone).Pluck("id", &blockers).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	err = database.DB.Model(&models.Task{}).Where("parent_id = ?", taskID).Where(notDone).Pluck("id", &subtasks).Error
+	return blockers, subtasks, err
+}
+
+// checkCanComplete rejects finishing a task while a blocker or subtask is
+// unfinished, writing the 409 response itself
+func checkCanComplete(c *gin.Context, taskID uint) bool {
+	blockers, subtasks, err := unfinishedPrerequisites(taskID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check task dependencies"})
+		return false
+	}
+	if len(blockers) > 0 || len(subtasks) > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":               "Task cannot be finished while blockers or subtasks are unfinished",
+			"unfinished_blockers": blockers,
+			"unfinished_subtasks": subtasks,
+		})
+		return false
+	}
+	return true
+}
+
+// taskSummary is the short form of a task used in dependency listings
+func taskSummary(task models.Task) gin.H {
+	return gin.H{
+		"id":         task.ID,
+		"title":      task.Title,
+		"status":     task.Status,
+		"project_id": task.ProjectID,
+	}
+}
+
+// SetTaskParent handles making a task a subtask of another task in the same
+// project, or detaching it with a null parent_id
+func SetTaskParent(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req ParentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, role, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if !canEdit(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewers cannot modify tasks"})
+		return
+	}
+
+	if req.ParentID != nil {
+		var parent models.Task
+		if err := database.DB.Where("id = ? AND project_id = ?", *req.ParentID, task.ProjectID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent task not found in this project"})
+			return
+		}
+	}
+
+	updated := task
+	updated.ParentID = req.ParentID
+	changes := models.TaskChanges(task, updated)
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", dependencyLock).Error; err != nil {
+			return err
+		}
+		if req.ParentID != nil {
+			cycle, err := createsParentCycle(tx, task.ID, *req.ParentID)
+			if err != nil {
+				return err
+			}
+			if cycle {
+				return errCycle
+			}
+		}
+		if err := tx.Model(&task).Update("parent_id", req.ParentID).Error; err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return recordTaskEvent(tx, task, userID, models.TaskUpdated, changes)
+	})
+	if errors.Is(err, errCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": "A task cannot be a subtask of itself or of its own subtasks"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update parent task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Parent task updated successfully",
+		"task": gin.H{
+			"id":        task.ID,
+			"title":     task.Title,
+			"parent_id": req.ParentID,
+		},
+	})
+}
+
+// GetSubtasks lists a task's direct subtasks
+func GetSubtasks(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	task, _, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	var subtasks []models.Task
+	if err := database.DB.Preload("Assignee").Where("parent_id = ?", task.ID).Order("id").Find(&subtasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subtasks"})
+		return
+	}
+
+	subtaskList := make([]gin.H, 0, len(subtasks))
+	done := 0
+	for _, subtask := range subtasks {
+		if subtask.Status == task.Project.Workflow.DoneStatus {
+			done++
+		}
+		summary := taskSummary(subtask)
+		summary["assignee"] = userName(subtask.Assignee)
+		subtaskList = append(subtaskList, summary)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"task_id":  task.ID,
+		"subtasks": subtaskList,
+		"progress": gin.H{"done": done, "total": len(subtasks)},
+	})
+}
+
+// GetTaskDependencies lists the tasks blocking a task and the tasks it blocks
+func GetTaskDependencies(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	task, _, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	var blockedBy, blocks []models.TaskDependency
+	if err := database.DB.Preload("Blocker").Where("blocked_id = ?", task.ID).Order("id").Find(&blockedBy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dependencies"})
+		return
+	}
+	if err := database.DB.Preload("Blocked").Where("blocker_id = ?", task.ID).Order("id").Find(&blocks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dependencies"})
+		return
+	}
+
+	blockedByList := make([]gin.H, 0, len(blockedBy))
+	for _, dependency := range blockedBy {
+		if dependency.Blocker != nil {
+			blockedByList = append(blockedByList, taskSummary(*dependency.Blocker))
+		}
+	}
+	blocksList := make([]gin.H, 0, len(blocks))
+	for _, dependency := range blocks {
+		if dependency.Blocked != nil {
+			blocksList = append(blocksList, taskSummary(*dependency.Blocked))
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"task_id":    task.ID,
+		"blocked_by": blockedByList,
+		"blocks":     blocksList,
+	})
+}
+
+// AddTaskDependency handles recording that another task blocks this one
+// ({"blocked_by_id": X}) or is blocked by it ({"blocks_id": X})
+func AddTaskDependency(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req DependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if (req.BlockedByID == nil) == (req.BlocksID == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Provide exactly one of blocked_by_id or blocks_id"})
+		return
+	}
+
+	task, role, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	otherID := req.BlockedByID
+	if otherID == nil {
+		otherID = req.BlocksID
+	}
+	other, otherRole, ok := findTaskForUser(*otherID, userID)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Related task not found"})
+		return
+	}
+	if !canEdit(role) || !canEdit(otherRole) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewers cannot modify tasks"})
+		return
+	}
+	if other.ID == task.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A task cannot depend on itself"})
+		return
+	}
+
+	blocker, blocked := other, task
+	if req.BlocksID != nil {
+		blocker, blocked = task, other
+	}
+
+	// A finished task can't gain an unfinished blocker
+	if blocked.Status == blocked.Project.Workflow.DoneStatus && blocker.Status != blocker.Project.Workflow.DoneStatus {
+		c.JSON(http.StatusConflict, gin.H{"error": "A finished task cannot be blocked by an unfinished task"})
+		return
+	}
+
+	dependency := models.TaskDependency{BlockerID: blocker.ID, BlockedID: blocked.ID}
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", dependencyLock).Error; err != nil {
+			return err
+		}
+		cycle, err := createsDependencyCycle(tx, blocker.ID, blocked.ID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			return errCycle
+		}
+		return tx.Where(models.TaskDependency{BlockerID: blocker.ID, BlockedID: blocked.ID}).FirstOrCreate(&dependency).Error
+	})
+	if errors.Is(err, errCycle) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This dependency would create a cycle"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add dependency"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Dependency added successfully",
+		"dependency": gin.H{
+			"id":      dependency.ID,
+			"blocker": taskSummary(blocker),
+			"blocked": taskSummary(blocked),
+		},
+	})
+}
+
+// RemoveTaskDependency handles removing a dependency between two tasks, in
+// whichever direction it exists
+func RemoveTaskDependency(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	task, role, ok := findTaskForUser(c.Param("id"), userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if !canEdit(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewers cannot modify tasks"})
+		return
+	}
+
+	otherID := c.Param("other_id")
+	result := database.DB.
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", task.ID, otherID, otherID, task.ID).
+		Delete(&models.TaskDependency{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove dependency"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dependency not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Dependency removed successfully",
+	})
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"task-management-task-service/internal/models"
+)
+
+// testTx opens the database in TEST_DATABASE_URL and returns a transaction
+// that is rolled back when the test ends. Tests are skipped without one.
+func testTx(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.Project{}, &models.Task{}, &models.TaskDependency{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// createTasks adds a project with n tasks and returns the task IDs
+func createTasks(t *testing.T, tx *gorm.DB, n int) []uint {
+	t.Helper()
+	user := models.User{Name: "Test", Email: "dependencies-test@example.com", Password: "x"}
+	if err := tx.Create(&user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	project := models.Project{Name: "Dependencies", OwnerID: user.ID, Workflow: models.DefaultWorkflow()}
+	if err := tx.Create(&project).Error; err != nil {
+		t.Fatalf("create project: %v", err)
+	}
+
+	ids := make([]uint, n)
+	for i := range ids {
+		task := models.Task{Title: "Task", ProjectID: project.ID, CreatorID: &user.ID}
+		if err := tx.Create(&task).Error; err != nil {
+			t.Fatalf("create task: %v", err)
+		}
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func TestCreatesDependencyCycle(t *testing.T) {
+	tx := testTx(t)
+	ids := createTasks(t, tx, 4)
+	a, b, c, d := ids[0], ids[1], ids[2], ids[3]
+
+	// A blocks B, B blocks C
+	for _, dependency := range []models.TaskDependency{{BlockerID: a, BlockedID: b}, {BlockerID: b, BlockedID: c}} {
+		if err := tx.Create(&dependency).Error; err != nil {
+			t.Fatalf("create dependency: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		blocker   uint
+		blocked   uint
+		wantCycle bool
+	}{
+		{"self", a, a, true},
+		{"direct", b, a, true},
+		{"indirect A→B→C→A", c, a, true},
+		{"chain extended", c, d, false},
+		{"shortcut", a, c, false},
+		{"unrelated", d, a, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cycle, err := createsDependencyCycle(tx, test.blocker, test.blocked)
+			if err != nil {
+				t.Fatalf("createsDependencyCycle: %v", err)
+			}
+			if cycle != test.wantCycle {
+				t.Errorf("createsDependencyCycle(%d, %d) = %v, want %v", test.blocker, test.blocked, cycle, test.wantCycle)
+			}
+		})
+	}
+}
+
+func TestCreatesParentCycle(t *testing.T) {
+	tx := testTx(t)
+	ids := createTasks(t, tx, 4)
+	a, b, c, d := ids[0], ids[1], ids[2], ids[3]
+
+	// A is the parent of B, B of C
+	if err := tx.Model(&models.Task{}).Where("id = ?", b).Update("parent_id", a).Error; err != nil {
+		t.Fatalf("set parent: %v", err)
+	}
+	if err := tx.Model(&models.Task{}).Where("id = ?", c).Update("parent_id", b).Error; err != nil {
+		t.Fatalf("set parent: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		task      uint
+		parent    uint
+		wantCycle bool
+	}{
+		{"self", a, a, true},
+		{"child as parent", a, b, true},
+		{"grandchild as parent", a, c, true},
+		{"reparent within tree", c, a, false},
+		{"unrelated", d, c, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cycle, err := createsParentCycle(tx, test.task, test.parent)
+			if err != nil {
+				t.Fatalf("createsParentCycle: %v", err)
+			}
+			if cycle != test.wantCycle {
+				t.Errorf("createsParentCycle(%d, %d) = %v, want %v", test.task, test.parent, cycle, test.wantCycle)
+			}
+		})
+	}
+}
+
+func TestAdvisoryLockKey(t *testing.T) {
+	if advisoryLockKey("task_dependencies") != advisoryLockKey("task_dependencies") {
+		t.Error("advisoryLockKey is not stable")
+	}
+	if advisoryLockKey("task_dependencies") == advisoryLockKey("task_events") {
+		t.Error("different names gave the same key")
+	}
+}
@@ -1,10 +1,19 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"net/http"
+	"strconv"
+	"strings"
+	"task-management-pkg/pagination"
 	"task-management-task-service/internal/database"
 	"task-management-task-service/internal/models"
+	"task-management-task-service/internal/notify"
+	"task-management-task-service/internal/recurrence"
 	"time"
 )
 
@@ -16,37 +25,132 @@ type TaskRequest struct {
 	Estimate    string `json:"estimate"`
 	Status      string `json:"status"`
 	DueDate     string `json:"due_date"`
+	AssigneeID  *uint  `json:"assignee_id"`
+	ParentID    *uint  `json:"parent_id"`
+	Recurrence  string `json:"recurrence"`
 }
 
-// Validation functions (same as monolith)
-func isValidStatus(status string) bool {
-	validStatuses := []string{"Not Started", "In Progress", "Done", "Blocked"}
-	for _, v := range validStatuses {
-		if v == status {
-			return true
-		}
+type AssignRequest struct {
+	AssigneeID *uint `json:"assignee_id"`
+}
+
+// validateTaskFields checks a task's status, priority and estimate against
+// its project's workflow, writing the 400 response itself. Empty values are
+// not checked.
+func validateTaskFields(c *gin.Context, workflow models.Workflow, status, priority, estimate string) bool {
+	if status != "" && !workflow.HasStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: " + strings.Join(workflow.Statuses, ", ")})
+		return false
+	}
+	if priority != "" && !workflow.HasPriority(priority) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: " + strings.Join(workflow.Priorities, ", ")})
+		return false
+	}
+	if estimate != "" && !workflow.HasEstimate(estimate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: " + strings.Join(workflow.Estimates, ", ")})
+		return false
 	}
-	return false
+	return true
 }
 
-func isValidPriority(priority string) bool {
-	validPriorities := []string{"Low", "Medium", "High", "Urgent"}
-	for _, v := range validPriorities {
-		if v == priority {
-			return true
-		}
+// errWorkflowChanged is returned by lockWorkflow when the task's values left
+// its project's workflow since they were validated
+var errWorkflowChanged = errors.New("workflow changed")
+
+// lockWorkflow locks the task's project row for the rest of the transaction
+// and checks the task's status, priority and estimate are still in its
+// workflow. Workflow updates lock the row for update before checking which
+// values tasks use, so they wait for this transaction and see its task.
+func lockWorkflow(tx *gorm.DB, task models.Task) error {
+	var project models.Project
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&project, task.ProjectID).Error; err != nil {
+		return err
 	}
-	return false
+	workflow := project.Workflow
+	if !workflow.HasStatus(task.Status) ||
+		(task.Priority != "" && !workflow.HasPriority(task.Priority)) ||
+		(task.Estimate != "" && !workflow.HasEstimate(task.Estimate)) {
+		return errWorkflowChanged
+	}
+	return nil
 }
 
-func isValidEstimate(estimate string) bool {
-	validEstimates := []string{"S", "M", "L", "XL"}
-	for _, v := range validEstimates {
-		if v == estimate {
-			return true
-		}
+// workflowChanged answers a write that lost a race with a workflow update
+func workflowChanged(c *gin.Context) {
+	c.JSON(http.StatusConflict, gin.H{"error": "The project's workflow changed meanwhile. Check the task's status, priority and estimate and try again"})
+}
+
+// parseRecurrence validates a task's recurrence rule and returns it in
+// canonical form, writing the 400 response itself. Recurring tasks need a due
+// date to count occurrences from.
+func parseRecurrence(c *gin.Context, value string, dueDate *time.Time) (string, bool) {
+	if value == "" {
+		return "", true
+	}
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+		return "", false
+	}
+	if dueDate == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recurring tasks need a due_date"})
+		return "", false
+	}
+	return rule.String(), true
+}
+
+// workflowRank is the 1-based position of a task's value in the named list of
+// its project's workflow, or 0 when the value isn't in the list
+func workflowRank(column, list string) string {
+	return fmt.Sprintf("COALESCE((SELECT s.n FROM projects p, jsonb_array_elements_text(p.workflow->'%s') WITH ORDINALITY AS s(value, n) "+
+		"WHERE p.id = tasks.project_id AND s.value = tasks.%s), 0)", list, column)
+}
+
+// taskSortColumns are the fields GET /tasks can be sorted by. Status, priority
+// and estimate sort in their project's workflow order rather than
+// alphabetically, and tasks without a due date sort after those with one.
+var taskSortColumns = map[string]string{
+	"id":         "tasks.id",
+	"title":      "tasks.title",
+	"status":     workflowRank("status", "statuses"),
+	"priority":   workflowRank("priority", "priorities"),
+	"estimate":   workflowRank("estimate", "estimates"),
+	"due_date":   "COALESCE(tasks.due_date, 'infinity')",
+	"created_at": "tasks.created_at",
+	"updated_at": "tasks.updated_at",
+}
+
+// findAssignee verifies the assignee exists and can access the task's project
+func findAssignee(c *gin.Context, assigneeID uint, project models.Project) (models.User, bool) {
+	var assignee models.User
+	if err := database.DB.First(&assignee, assigneeID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee not found"})
+		return assignee, false
+	}
+	if _, ok := projectRole(project, assignee.ID); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Assignee is not a member of the project"})
+		return assignee, false
+	}
+	return assignee, true
+}
+
+// parseQueryID reads a numeric ID from the query string, answering 400 when
+// it isn't one
+func parseQueryID(c *gin.Context, name string) (uint, bool) {
+	id, err := strconv.ParseUint(c.Query(name), 10, 64)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + ". Use a positive number"})
+		return 0, false
 	}
-	return false
+	return uint(id), true
+}
+
+// userName returns the user's name, or an empty string for unassigned relations
+func userName(user *models.User) string {
+	if user == nil {
+		return ""
+	}
+	return user.Name
 }
 
 // CreateTask handles task creation
@@ -59,25 +163,37 @@ func CreateTask(c *gin.Context) {
 		return
 	}
 
-	// Verify project ownership
-	var project models.Project
-	if err := database.DB.Where("id = ? AND owner_id = ?", req.ProjectID, userID).First(&project).Error; err != nil {
+	// Verify project access (owners and editors can create tasks)
+	project, ok := findProjectForRole(req.ProjectID, userID, true)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or access denied"})
 		return
 	}
 
+	// Tasks are assigned to their creator unless someone else is given
+	assigneeID := &userID
+	if req.AssigneeID != nil {
+		if _, ok := findAssignee(c, *req.AssigneeID, project); !ok {
+			return
+		}
+		assigneeID = req.AssigneeID
+	}
+
 	// Validate optional fields
-	if req.Status != "" && !isValidStatus(req.Status) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: Not Started, In Progress, Done, Blocked"})
+	if !validateTaskFields(c, project.Workflow, req.Status, req.Priority, req.Estimate) {
 		return
 	}
-	if req.Priority != "" && !isValidPriority(req.Priority) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: Low, Medium, High, Urgent"})
-		return
+	if req.Status == "" {
+		req.Status = project.Workflow.InitialStatus()
 	}
-	if req.Estimate != "" && !isValidEstimate(req.Estimate) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: S, M, L, XL"})
-		return
+
+	// Subtasks must live in the same project as their parent
+	if req.ParentID != nil {
+		var parent models.Task
+		if err := database.DB.Where("id = ? AND project_id = ?", *req.ParentID, req.ProjectID).First(&parent).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Parent task not found in this project"})
+			return
+		}
 	}
 
 	// Parse due date if provided
@@ -91,26 +207,45 @@ func CreateTask(c *gin.Context) {
 		dueDate = &parsed
 	}
 
+	rule, ok := parseRecurrence(c, req.Recurrence, dueDate)
+	if !ok {
+		return
+	}
+
 	// Create task
 	task := models.Task{
 		Title:       req.Title,
 		Description: req.Description,
 		ProjectID:   req.ProjectID,
-		AssigneeID:  &userID,
+		ParentID:    req.ParentID,
+		AssigneeID:  assigneeID,
 		CreatorID:   &userID,
 		Status:      req.Status,
 		Estimate:    req.Estimate,
 		Priority:    req.Priority,
 		DueDate:     dueDate,
+		Recurrence:  rule,
 	}
 
-	if err := database.DB.Create(&task).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockWorkflow(tx, task); err != nil {
+			return err
+		}
+		if err := tx.Create(&task).Error; err != nil {
+			return err
+		}
+		return recordTaskEvent(tx, task, userID, models.TaskCreated, models.TaskChanges(models.Task{}, task))
+	})
+	if errors.Is(err, errWorkflowChanged) {
+		workflowChanged(c)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
 		return
 	}
 
-	// TODO: Send notification to Notification Service (future microservice)
-	// For now, we skip the email notification
+	notify.Publish(notify.TaskCreated, userID, task, nil)
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Task created successfully",
@@ -119,10 +254,13 @@ func CreateTask(c *gin.Context) {
 			"title":       task.Title,
 			"description": task.Description,
 			"project_id":  task.ProjectID,
+			"parent_id":   task.ParentID,
+			"assignee_id": task.AssigneeID,
 			"status":      task.Status,
 			"estimate":    task.Estimate,
 			"priority":    task.Priority,
 			"due_date":    task.DueDate,
+			"recurrence":  task.Recurrence,
 			"created_at":  task.CreatedAt,
 		},
 	})
@@ -132,23 +270,59 @@ func CreateTask(c *gin.Context) {
 func GetTasks(c *gin.Context) {
 	userID := c.GetUint("user_id")
 
-	// Base query with preloading for enriched data
-	query := database.DB.Preload("Project").Preload("Creator").Preload("Assignee").Where("assignee_id = ?", userID)
+	page, err := pagination.Parse(c, "tasks", taskSortColumns, "id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Base query scoped to projects the user can access
+	query := database.DB.Model(&models.Task{}).Where("project_id IN (?)", accessibleProjectIDs(userID))
 
-	// Filter by project_id
+	// Filter by project_id; its workflow then validates the status, priority
+	// and estimate filters
+	var workflow *models.Workflow
 	if projectID := c.Query("project_id"); projectID != "" {
-		var project models.Project
-		if err := database.DB.Where("id = ? AND owner_id = ?", projectID, userID).First(&project).Error; err != nil {
+		project, ok := findProjectForRole(projectID, userID, false)
+		if !ok {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or access denied"})
 			return
 		}
+		workflow = &project.Workflow
 		query = query.Where("project_id = ?", projectID)
 	}
 
+	// Filter by parent task; parent_id=none lists top-level tasks only
+	if parentID := c.Query("parent_id"); parentID == "none" {
+		query = query.Where("parent_id IS NULL")
+	} else if parentID != "" {
+		parentID, ok := parseQueryID(c, "parent_id")
+		if !ok {
+			return
+		}
+		query = query.Where("parent_id = ?", parentID)
+	}
+
+	// Filter by assignee and creator
+	if c.Query("assignee_id") != "" {
+		assigneeID, ok := parseQueryID(c, "assignee_id")
+		if !ok {
+			return
+		}
+		query = query.Where("assignee_id = ?", assigneeID)
+	}
+	if c.Query("creator_id") != "" {
+		creatorID, ok := parseQueryID(c, "creator_id")
+		if !ok {
+			return
+		}
+		query = query.Where("creator_id = ?", creatorID)
+	}
+
 	// Filter by status
 	if status := c.Query("status"); status != "" {
-		if !isValidStatus(status) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: Not Started, In Progress, Done, Blocked"})
+		if workflow != nil && !workflow.HasStatus(status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: " + strings.Join(workflow.Statuses, ", ")})
 			return
 		}
 		query = query.Where("status = ?", status)
@@ -156,8 +330,8 @@ func GetTasks(c *gin.Context) {
 
 	// Filter by priority
 	if priority := c.Query("priority"); priority != "" {
-		if !isValidPriority(priority) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: Low, Medium, High, Urgent"})
+		if workflow != nil && !workflow.HasPriority(priority) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: " + strings.Join(workflow.Priorities, ", ")})
 			return
 		}
 		query = query.Where("priority = ?", priority)
@@ -165,8 +339,8 @@ func GetTasks(c *gin.Context) {
 
 	// Filter by estimate
 	if estimate := c.Query("estimate"); estimate != "" {
-		if !isValidEstimate(estimate) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: S, M, L, XL"})
+		if workflow != nil && !workflow.HasEstimate(estimate) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: " + strings.Join(workflow.Estimates, ", ")})
 			return
 		}
 		query = query.Where("estimate = ?", estimate)
@@ -190,13 +364,25 @@ func GetTasks(c *gin.Context) {
 		query = query.Where("due_date <= ?", date)
 	}
 
-	// Execute query
+	// Count every match, then fetch one page with preloading for enriched data
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
+		return
+	}
+
 	var tasks []models.Task
-	if err := query.Find(&tasks).Error; err != nil {
+	if err := page.Apply(query.Preload("Project").Preload("Creator").Preload("Assignee")).Find(&tasks).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
 		return
 	}
 
+	hasMore := len(tasks) > page.Limit
+	if hasMore {
+		tasks = tasks[:page.Limit]
+	}
+
 	// Build enriched response with cross-service data
 	var taskList []gin.H
 	for _, task := range tasks {
@@ -208,19 +394,29 @@ func GetTasks(c *gin.Context) {
 				"id":   task.Project.ID,
 				"name": task.Project.Name,
 			},
+			"parent_id":  task.ParentID,
 			"creator":    task.Creator.Name,
-			"assignee":   task.Assignee.Name,
+			"assignee":   userName(task.Assignee),
 			"status":     task.Status,
 			"priority":   task.Priority,
 			"estimate":   task.Estimate,
 			"due_date":   task.DueDate,
+			"recurrence": task.Recurrence,
 			"created_at": task.CreatedAt,
 			"updated_at": task.UpdatedAt,
 		})
 	}
 
+	var lastID uint
+	if len(tasks) > 0 {
+		lastID = tasks[len(tasks)-1].ID
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"tasks": taskList,
+		"tasks":       taskList,
+		"total":       total,
+		"limit":       page.Limit,
+		"next_cursor": pagination.NextCursor(hasMore, lastID),
 	})
 }
 
@@ -230,13 +426,13 @@ func GetTaskByID(c *gin.Context) {
 	taskID := c.Param("id")
 
 	var task models.Task
-	if err := database.DB.Preload("Project").Preload("Creator").Preload("Assignee").Where("id = ? AND assignee_id = ?", taskID, userID).First(&task).Error; err != nil {
+	if err := database.DB.Preload("Project").Preload("Creator").Preload("Assignee").Where("id = ?", taskID).First(&task).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
 
-	// Additional authorization - verify project ownership
-	if task.Project.OwnerID != userID {
+	// Authorize through project membership
+	if _, ok := projectRole(task.Project, userID); !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
@@ -250,14 +446,18 @@ func GetTaskByID(c *gin.Context) {
 				"id":   task.Project.ID,
 				"name": task.Project.Name,
 			},
-			"creator":    task.Creator.Name,
-			"assignee":   task.Assignee.Name,
-			"status":     task.Status,
-			"priority":   task.Priority,
-			"estimate":   task.Estimate,
-			"due_date":   task.DueDate,
-			"created_at": task.CreatedAt,
-			"updated_at": task.UpdatedAt,
+			"parent_id":          task.ParentID,
+			"creator":            task.Creator.Name,
+			"assignee":           userName(task.Assignee),
+			"status":             task.Status,
+			"priority":           task.Priority,
+			"estimate":           task.Estimate,
+			"due_date":           task.DueDate,
+			"recurrence":         task.Recurrence,
+			"occurrence":         task.Occurrence,
+			"next_occurrence_id": task.NextOccurrenceID,
+			"created_at":         task.CreatedAt,
+			"updated_at":         task.UpdatedAt,
 		},
 	})
 }
@@ -275,36 +475,66 @@ func UpdateTask(c *gin.Context) {
 
 	// Find and authorize task
 	var task models.Task
-	if err := database.DB.Preload("Project").Where("id = ? AND assignee_id = ?", taskID, userID).First(&task).Error; err != nil {
+	if err := database.DB.Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
 
-	if task.Project.OwnerID != userID {
+	role, ok := projectRole(task.Project, userID)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
+	if !canEdit(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewers cannot modify tasks"})
+		return
+	}
 
 	// Validate project change if requested
+	project := task.Project
 	if req.ProjectID != task.ProjectID {
-		var newProject models.Project
-		if err := database.DB.Where("id = ? AND owner_id = ?", req.ProjectID, userID).First(&newProject).Error; err != nil {
+		var subtasks int64
+		if err := database.DB.Model(&models.Task{}).Where("parent_id = ?", task.ID).Count(&subtasks).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
+			return
+		}
+		if task.ParentID != nil || subtasks > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Detach the task from its parent and subtasks before moving it to another project"})
+			return
+		}
+		if project, ok = findProjectForRole(req.ProjectID, userID, true); !ok {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Target project not found or access denied"})
 			return
 		}
 	}
 
+	// Reassign only when an assignee is given; use PATCH /tasks/:id/assign to unassign
+	if req.AssigneeID != nil {
+		if _, ok := findAssignee(c, *req.AssigneeID, project); !ok {
+			return
+		}
+		task.AssigneeID = req.AssigneeID
+	}
+
 	// Validate optional fields
-	if req.Status != "" && !isValidStatus(req.Status) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Use: Not Started, In Progress, Done, Blocked"})
+	// An omitted status keeps the current one; moves must follow the workflow
+	workflow := project.Workflow
+	if req.Status == "" {
+		req.Status = task.Status
+	}
+	if !validateTaskFields(c, workflow, req.Status, req.Priority, req.Estimate) {
 		return
 	}
-	if req.Priority != "" && !isValidPriority(req.Priority) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid priority. Use: Low, Medium, High, Urgent"})
+	if req.ProjectID == task.ProjectID && !workflow.CanTransition(task.Status, req.Status) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   fmt.Sprintf("Cannot move a task from %s to %s", task.Status, req.Status),
+			"allowed": workflow.NextStatuses(task.Status),
+		})
 		return
 	}
-	if req.Estimate != "" && !isValidEstimate(req.Estimate) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid estimate. Use: S, M, L, XL"})
+
+	// A task can only be finished once its blockers and subtasks are
+	if req.Status == workflow.DoneStatus && task.Status != req.Status && !checkCanComplete(c, task.ID) {
 		return
 	}
 
@@ -319,11 +549,13 @@ func UpdateTask(c *gin.Context) {
 		dueDate = &parsed
 	}
 
-	// Track changes for notification (future use)
-	//originalTitle := task.Title
-	//originalStatus := task.Status
-	//originalPriority := task.Priority
-	//originalEstimate := task.Estimate
+	rule, ok := parseRecurrence(c, req.Recurrence, dueDate)
+	if !ok {
+		return
+	}
+
+	// Keep the original for the audit trail
+	original := task
 
 	// Update task
 	task.Title = req.Title
@@ -333,14 +565,55 @@ func UpdateTask(c *gin.Context) {
 	task.Priority = req.Priority
 	task.Estimate = req.Estimate
 	task.DueDate = dueDate
+	task.Recurrence = rule
+	task.Project = project
+
+	changes := models.TaskChanges(original, task)
+	var next *models.Task
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		// The scheduler owns the series links, so don't write back stale ones
+		if err := tx.Omit("Occurrence", "NextOccurrenceID").Save(&task).Error; err != nil {
+			return err
+		}
+		// Locked after the task row, in the order the scheduler locks them
+		if err := lockWorkflow(tx, task); err != nil {
+			return err
+		}
+		if len(changes) > 0 {
+			if err := recordTaskEvent(tx, task, userID, models.TaskUpdated, changes); err != nil {
+				return err
+			}
+		}
 
-	if err := database.DB.Save(&task).Error; err != nil {
+		// Finishing a recurring task creates its next occurrence right away
+		var err error
+		next, err = recurrence.Materialize(tx, task.ID, userID, time.Now())
+		return err
+	})
+	if errors.Is(err, errWorkflowChanged) {
+		workflowChanged(c)
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
 		return
 	}
 
-	// TODO: Send change notification to Notification Service
-	// Track what changed: originalTitle vs task.Title, etc.
+	if len(changes) > 0 {
+		notify.Publish(notify.TaskUpdated, userID, task, changes)
+	}
+
+	var nextOccurrence gin.H
+	if next != nil {
+		notify.Publish(notify.TaskCreated, userID, *next, nil)
+		nextOccurrence = gin.H{
+			"id":         next.ID,
+			"title":      next.Title,
+			"status":     next.Status,
+			"due_date":   next.DueDate,
+			"occurrence": next.Occurrence,
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Task updated successfully",
@@ -349,10 +622,82 @@ func UpdateTask(c *gin.Context) {
 			"title":       task.Title,
 			"description": task.Description,
 			"project_id":  task.ProjectID,
+			"parent_id":   task.ParentID,
+			"assignee_id": task.AssigneeID,
 			"status":      task.Status,
 			"priority":    task.Priority,
 			"estimate":    task.Estimate,
 			"due_date":    task.DueDate,
+			"recurrence":  task.Recurrence,
+			"updated_at":  task.UpdatedAt,
+		},
+		"next_occurrence": nextOccurrence,
+	})
+}
+
+// AssignTask handles assigning a task to a project member, or unassigning it with a null assignee_id
+func AssignTask(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	taskID := c.Param("id")
+
+	var req AssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var task models.Task
+	if err := database.DB.Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	role, ok := projectRole(task.Project, userID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if !canEdit(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewers cannot assign tasks"})
+		return
+	}
+
+	var assignee models.User
+	if req.AssigneeID != nil {
+		if assignee, ok = findAssignee(c, *req.AssigneeID, task.Project); !ok {
+			return
+		}
+	}
+
+	assigned := task
+	assigned.AssigneeID = req.AssigneeID
+	changes := models.TaskChanges(task, assigned)
+
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&task).Update("assignee_id", req.AssigneeID).Error; err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		return recordTaskEvent(tx, task, userID, models.TaskUpdated, changes)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign task"})
+		return
+	}
+
+	if len(changes) > 0 {
+		notify.Publish(notify.TaskUpdated, userID, assigned, changes)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task assigned successfully",
+		"task": gin.H{
+			"id":          task.ID,
+			"title":       task.Title,
+			"assignee_id": req.AssigneeID,
+			"assignee":    assignee.Name,
 			"updated_at":  task.UpdatedAt,
 		},
 	})
@@ -364,21 +709,41 @@ func DeleteTask(c *gin.Context) {
 	taskID := c.Param("id")
 
 	var task models.Task
-	if err := database.DB.Preload("Project").Where("id = ? AND assignee_id = ?", taskID, userID).First(&task).Error; err != nil {
+	if err := database.DB.Preload("Project").Where("id = ?", taskID).First(&task).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
 
-	if task.Project.OwnerID != userID {
+	role, ok := projectRole(task.Project, userID)
+	if !ok {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
+	if !canEdit(role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewers cannot delete tasks"})
+		return
+	}
 
-	if err := database.DB.Delete(&task).Error; err != nil {
+	err := database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&task).Error; err != nil {
+			return err
+		}
+		// Subtasks outlive their parent as top-level tasks
+		if err := tx.Model(&models.Task{}).Where("parent_id = ?", task.ID).Update("parent_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("blocker_id = ? OR blocked_id = ?", task.ID, task.ID).Delete(&models.TaskDependency{}).Error; err != nil {
+			return err
+		}
+		return recordTaskEvent(tx, task, userID, models.TaskDeleted, models.TaskChanges(task, models.Task{}))
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
 		return
 	}
 
+	notify.Publish(notify.TaskDeleted, userID, task, nil)
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Task deleted successfully",
 	})
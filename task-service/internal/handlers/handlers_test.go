@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"errors"
+	"testing"
+
+	"task-management-task-service/internal/models"
+)
+
+func TestLockWorkflow(t *testing.T) {
+	tx := testTx(t)
+	ids := createTasks(t, tx, 1)
+	var task models.Task
+	if err := tx.First(&task, ids[0]).Error; err != nil {
+		t.Fatalf("load task: %v", err)
+	}
+	task.Status, task.Priority = "In Progress", "High"
+
+	if err := lockWorkflow(tx, task); err != nil {
+		t.Fatalf("lockWorkflow with the default workflow: %v", err)
+	}
+
+	// The workflow drops "High" after the task was validated
+	workflow := models.DefaultWorkflow()
+	workflow.Priorities = []string{"Low", "Medium"}
+	if err := tx.Model(&models.Project{}).Where("id = ?", task.ProjectID).Update("workflow", workflow).Error; err != nil {
+		t.Fatalf("update workflow: %v", err)
+	}
+	if err := lockWorkflow(tx, task); !errors.Is(err, errWorkflowChanged) {
+		t.Errorf("lockWorkflow = %v, want errWorkflowChanged", err)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+)
+
+// recordTaskEvent appends an entry to a task's audit trail
+func recordTaskEvent(tx *gorm.DB, task models.Task, actorID uint, action string, changes models.FieldChanges) error {
+	return tx.Create(&models.TaskEvent{
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		ActorID:   actorID,
+		Action:    action,
+		Changes:   changes,
+	}).Error
+}
+
+// GetTaskHistory returns a task's audit trail, oldest first. History stays
+// readable after the task is deleted. Each entry belongs to the project the
+// task was in at the time, so a task that moved shows each user only the
+// entries of projects they can access.
+func GetTaskHistory(c *gin.Context) {
+	userID := c.GetUint("user_id")
+	taskID := c.Param("id")
+
+	var task models.Task
+	if err := database.DB.Unscoped().Where("id = ?", taskID).First(&task).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	visible := func() *gorm.DB {
+		return database.DB.Where("task_id = ? AND project_id IN (?)", task.ID, accessibleProjectIDs(userID))
+	}
+	var count int64
+	if err := visible().Model(&models.TaskEvent{}).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch task history"})
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	query := visible().Preload("Actor")
+	if field := c.Query("field"); field != "" {
+		filter, _ := json.Marshal([]gin.H{{"field": field}})
+		query = query.Where("changes @> ?", string(filter))
+	}
+
+	var events []models.TaskEvent
+	if err := query.Order("created_at, id").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch task history"})
+		return
+	}
+
+	history := make([]gin.H, 0, len(events))
+	for _, event := range events {
+		history = append(history, gin.H{
+			"id":     event.ID,
+			"action": event.Action,
+			"actor": gin.H{
+				"id":   event.ActorID,
+				"name": userName(event.Actor),
+			},
+			"changes":    event.Changes,
+			"created_at": event.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"task_id": task.ID,
+		"history": history,
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"net/http"
+	"task-management-pkg/pagination"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+	"time"
+)
+
+// notificationSortColumns are the fields GET /notifications can be sorted by
+var notificationSortColumns = map[string]string{
+	"id":         "notifications.id",
+	"created_at": "notifications.created_at",
+}
+
+// GetNotifications lists the user's notifications, newest first; ?unread=true
+// limits them to unread ones
+func GetNotifications(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	page, err := pagination.Parse(c, "notifications", notificationSortColumns, "-id")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := database.DB.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if c.Query("unread") == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total, unread int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+	if err := database.DB.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&unread).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	var notifications []models.Notification
+	if err := page.Apply(query).Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	hasMore := len(notifications) > page.Limit
+	if hasMore {
+		notifications = notifications[:page.Limit]
+	}
+
+	var lastID uint
+	if len(notifications) > 0 {
+		lastID = notifications[len(notifications)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"unread":        unread,
+		"total":         total,
+		"limit":         page.Limit,
+		"next_cursor":   pagination.NextCursor(hasMore, lastID),
+	})
+}
+
+// MarkNotificationRead marks one of the user's notifications as read
+func MarkNotificationRead(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var notification models.Notification
+	if err := database.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&notification).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	if notification.ReadAt == nil {
+		now := time.Now()
+		if err := database.DB.Model(&notification).Update("read_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Notification marked as read",
+		"notification": notification,
+	})
+}
+
+// MarkAllNotificationsRead marks every unread notification of the user as read
+func MarkAllNotificationsRead(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	result := database.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "All notifications marked as read",
+		"updated": result.RowsAffected,
+	})
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+
+	// searchQuery parses q with web-search syntax: quoted phrases, OR, and -excluded words
+	searchQuery = "websearch_to_tsquery('english', ?)"
+
+	// ts_headline marks matches with these private-use characters rather than
+	// tags, so the text can be HTML-escaped before they become <mark> tags
+	highlightStart = "\uE000"
+	highlightStop  = "\uE001"
+	highlightSel   = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `"`
+)
+
+// highlightTags turns the escaped match markers into <mark> tags
+var highlightTags = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+// highlight makes a ts_headline result safe to render as HTML: the task's
+// text is escaped and only the <mark> tags around matches are markup
+func highlight(headline string) string {
+	return highlightTags.Replace(html.EscapeString(headline))
+}
+
+// searchHit is a matching task's rank and highlighted text
+type searchHit struct {
+	ID             uint
+	Rank           float64
+	TitleHighlight string
+	Snippet        string
+}
+
+// SearchTasks handles full-text search over the titles and descriptions of
+// tasks the user can access, best matches first
+func SearchTasks(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Search query q is required"})
+		return
+	}
+
+	limit := defaultSearchLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit. Use a positive number"})
+			return
+		}
+		limit = min(parsed, maxSearchLimit)
+	}
+	offset := 0
+	if value := c.Query("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset. Use a non-negative number"})
+			return
+		}
+		offset = parsed
+	}
+
+	// Same scoping as GetTasks: only projects the user can access
+	query := database.DB.Model(&models.Task{}).
+		Where("project_id IN (?)", accessibleProjectIDs(userID)).
+		Where("search_vector @@ "+searchQuery, q)
+
+	if projectID := c.Query("project_id"); projectID != "" {
+		if _, ok := findProjectForRole(projectID, userID, false); !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or access denied"})
+			return
+		}
+		query = query.Where("project_id = ?", projectID)
+	}
+
+	query = query.Session(&gorm.Session{})
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tasks"})
+		return
+	}
+
+	var hits []searchHit
+	err := query.
+		Select("tasks.id, "+
+			"ts_rank(search_vector, "+searchQuery+") AS rank, "+
+			"ts_headline('english', title, "+searchQuery+", ?) AS title_highlight, "+
+			"ts_headline('english', coalesce(description, ''), "+searchQuery+", ?) AS snippet",
+			q, q, highlightSel+", HighlightAll=true", q, highlightSel+", MaxFragments=2, MaxWords=30, MinWords=10").
+		Order("rank DESC").Order("tasks.id").
+		Limit(limit).Offset(offset).
+		Scan(&hits).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tasks"})
+		return
+	}
+
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+
+	// Load the matching tasks with enriched data, then return them in rank order
+	var tasks []models.Task
+	if len(ids) > 0 {
+		if err := database.DB.Preload("Project").Preload("Creator").Preload("Assignee").Where("id IN ?", ids).Find(&tasks).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tasks"})
+			return
+		}
+	}
+	tasksByID := make(map[uint]models.Task, len(tasks))
+	for _, task := range tasks {
+		tasksByID[task.ID] = task
+	}
+
+	results := make([]gin.H, 0, len(hits))
+	for _, hit := range hits {
+		task, ok := tasksByID[hit.ID]
+		if !ok {
+			continue
+		}
+		results = append(results, gin.H{
+			"id":          task.ID,
+			"title":       task.Title,
+			"description": task.Description,
+			"project": gin.H{
+				"id":   task.Project.ID,
+				"name": task.Project.Name,
+			},
+			"creator":  task.Creator.Name,
+			"assignee": userName(task.Assignee),
+			"status":   task.Status,
+			"priority": task.Priority,
+			"due_date": task.DueDate,
+			"rank":     hit.Rank,
+			"highlights": gin.H{
+				"title":       highlight(hit.TitleHighlight),
+				"description": highlight(hit.Snippet),
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   q,
+		"results": results,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
@@ -0,0 +1,20 @@
+package handlers
+
+import "testing"
+
+func TestHighlightEscapesTaskText(t *testing.T) {
+	tests := []struct {
+		headline string
+		want     string
+	}{
+		{"fix " + highlightStart + "login" + highlightStop + " page", "fix <mark>login</mark> page"},
+		{"<script>alert(1)</script> " + highlightStart + "bug" + highlightStop, "&lt;script&gt;alert(1)&lt;/script&gt; <mark>bug</mark>"},
+		{`<img src=x onerror="alert(1)">`, "&lt;img src=x onerror=&#34;alert(1)&#34;&gt;"},
+		{"<mark>fake</mark>", "&lt;mark&gt;fake&lt;/mark&gt;"},
+	}
+	for _, test := range tests {
+		if got := highlight(test.headline); got != test.want {
+			t.Errorf("highlight(%q) = %q, want %q", test.headline, got, test.want)
+		}
+	}
+}
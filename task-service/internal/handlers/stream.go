@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+	"task-management-task-service/internal/stream"
+	"time"
+)
+
+const (
+	// streamReplayLimit bounds how many missed events a reconnecting client
+	// is sent; further behind, it is told to reload instead
+	streamReplayLimit = 500
+	// streamHeartbeat keeps idle connections from being closed by proxies
+	streamHeartbeat = 15 * time.Second
+	// streamAccessRefresh is how often a connection re-reads which projects
+	// the user can access, so membership changes apply to open streams
+	streamAccessRefresh = 30 * time.Second
+)
+
+// StreamTasks pushes changes to tasks in the user's projects as server-sent
+// events, optionally limited to one project with ?project_id=. Each event's
+// id is its task history ID; a client reconnecting with Last-Event-ID (or
+// ?last_event_id=) is first sent the events it missed.
+func StreamTasks(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var lastEventID uint
+	rawID := c.GetHeader("Last-Event-ID")
+	if rawID == "" {
+		rawID = c.Query("last_event_id")
+	}
+	if rawID != "" {
+		id, err := strconv.ParseUint(rawID, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+		lastEventID = uint(id)
+	}
+
+	var projectID uint
+	if rawProjectID := c.Query("project_id"); rawProjectID != "" {
+		project, ok := findProjectForRole(rawProjectID, userID, false)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found or access denied"})
+			return
+		}
+		projectID = project.ID
+	}
+
+	// Subscribe before replaying, so nothing committed in between is lost
+	sub := stream.Subscribe()
+	defer sub.Close()
+
+	var replay []stream.Event
+	var resetID uint
+	if lastEventID > 0 {
+		scope := database.DB.Where("task_events.id > ?", lastEventID)
+		if projectID != 0 {
+			scope = scope.Where("task_events.project_id = ?", projectID)
+		} else {
+			scope = scope.Where("task_events.project_id IN (?)", accessibleProjectIDs(userID))
+		}
+		events, err := stream.Load(scope, streamReplayLimit+1)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch missed events"})
+			return
+		}
+		if len(events) > streamReplayLimit {
+			// The reset carries the latest ID, so the client's next
+			// reconnect resumes from there instead of resetting again
+			err = database.DB.Model(&models.TaskEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&resetID).Error
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch missed events"})
+				return
+			}
+		} else {
+			replay = events
+		}
+	}
+
+	allowed, err := streamProjects(userID, projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	// Ask clients to reconnect quickly if the connection drops
+	fmt.Fprint(c.Writer, "retry: 3000\n\n")
+	if resetID != 0 {
+		// Too much was missed to replay; the client should reload its tasks
+		fmt.Fprintf(c.Writer, "id: %d\nevent: reset\ndata: {}\n\n", resetID)
+	}
+	replayed := make(map[uint]bool, len(replay))
+	for _, event := range replay {
+		writeStreamEvent(c, event)
+		replayed[event.ID] = true
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	refreshedAt := time.Now()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Fell too far behind; the client reconnects and replays
+				return
+			}
+			if event.ID <= lastEventID || replayed[event.ID] {
+				continue
+			}
+
+			if time.Since(refreshedAt) > streamAccessRefresh {
+				if projects, err := streamProjects(userID, projectID); err == nil {
+					allowed = projects
+					refreshedAt = time.Now()
+				}
+			}
+			if !allowed[event.ProjectID] {
+				continue
+			}
+
+			writeStreamEvent(c, event)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// streamProjects returns the projects whose events a stream may send: the
+// user's projects, or just projectID while the user can still access it
+func streamProjects(userID, projectID uint) (map[uint]bool, error) {
+	query := database.DB.Model(&models.Project{}).Where("id IN (?)", accessibleProjectIDs(userID))
+	if projectID != 0 {
+		query = query.Where("id = ?", projectID)
+	}
+
+	var ids []uint
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		allowed[id] = true
+	}
+	return allowed, nil
+}
+
+func writeStreamEvent(c *gin.Context, event stream.Event) {
+	data, _ := json.Marshal(event)
+	fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
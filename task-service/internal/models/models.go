@@ -1,7 +1,13 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"gorm.io/gorm"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,6 +28,7 @@ type Project struct {
 	Description string         `json:"description"`
 	OwnerID     uint           `json:"owner_id" gorm:"not null"`
 	Owner       *User          `json:"owner" gorm:"foreignKey:OwnerID"`
+	Workflow    Workflow       `json:"workflow" gorm:"type:jsonb"`
 	Tasks       []Task         `json:"tasks,omitempty" gorm:"foreignKey:ProjectID"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
@@ -34,6 +41,7 @@ type Task struct {
 	Description string         `json:"description"`
 	ProjectID   uint           `json:"project_id" gorm:"not null"`
 	Project     Project        `json:"project" gorm:"foreignKey:ProjectID"`
+	ParentID    *uint          `json:"parent_id" gorm:"index"`
 	AssigneeID  *uint          `json:"assignee_id"`
 	Assignee    *User          `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
 	CreatorID   *uint          `json:"creator_id" gorm:"not null"`
@@ -45,4 +53,305 @@ type Task struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	// Recurrence is an RRULE (e.g. FREQ=WEEKLY;BYDAY=MO). Each occurrence is
+	// its own task, numbered from 1 and linked to the one created after it.
+	Recurrence       string `json:"recurrence"`
+	Occurrence       int    `json:"occurrence" gorm:"not null;default:1"`
+	NextOccurrenceID *uint  `json:"next_occurrence_id"`
+
+	// SearchVector is maintained by PostgreSQL from the title (weighted
+	// higher) and description, and GIN-indexed for full-text search
+	SearchVector string `json:"-" gorm:"type:tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english', coalesce(title, '')), 'A') || setweight(to_tsvector('english', coalesce(description, '')), 'B')) STORED;index:idx_tasks_search,type:gin;->:false;<-:false"`
+}
+
+// Project member roles, from most to least privileged
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type ProjectMember struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID uint      `json:"project_id" gorm:"not null;uniqueIndex:idx_project_member"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_project_member"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Role      string    `json:"role" gorm:"not null;default:viewer"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskDependency records that BlockerID blocks BlockedID: the blocked task
+// cannot be completed until the blocker is Done
+type TaskDependency struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	BlockerID uint      `json:"blocker_id" gorm:"not null;uniqueIndex:idx_task_dependency"`
+	Blocker   *Task     `json:"blocker,omitempty" gorm:"foreignKey:BlockerID"`
+	BlockedID uint      `json:"blocked_id" gorm:"not null;uniqueIndex:idx_task_dependency;index"`
+	Blocked   *Task     `json:"blocked,omitempty" gorm:"foreignKey:BlockedID"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Comment is a discussion message on a task. Users mentioned by @email are
+// linked through comment_mentions.
+type Comment struct {
+	ID        uint           `json:"id" gorm:"primaryKey"`
+	TaskID    uint           `json:"task_id" gorm:"not null;index"`
+	AuthorID  uint           `json:"author_id" gorm:"not null"`
+	Author    *User          `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+	Body      string         `json:"body" gorm:"type:text;not null"`
+	Mentions  []User         `json:"mentions,omitempty" gorm:"many2many:comment_mentions"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+}
+
+// Notification types
+const (
+	NotificationMention = "mention"
+)
+
+// Notification is an in-app notification for a user
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null;index"`
+	Type      string     `json:"type" gorm:"not null"`
+	ActorID   *uint      `json:"actor_id"`
+	TaskID    *uint      `json:"task_id"`
+	CommentID *uint      `json:"comment_id"`
+	Message   string     `json:"message" gorm:"not null"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// RevokedToken is the shared denylist of access token IDs (jti claims),
+// written by auth-service on logout
+type RevokedToken struct {
+	JTI       string    `json:"jti" gorm:"primaryKey"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Task event actions
+const (
+	TaskCreated = "created"
+	TaskUpdated = "updated"
+	TaskDeleted = "deleted"
+)
+
+// TaskEvent is one entry in a task's audit trail: who did what, when, and
+// the before and after value of every field that changed. ProjectID is the
+// project the task was in at the time.
+type TaskEvent struct {
+	ID        uint         `json:"id" gorm:"primaryKey"`
+	TaskID    uint         `json:"task_id" gorm:"not null;index"`
+	ProjectID uint         `json:"project_id" gorm:"index"`
+	ActorID   uint         `json:"actor_id" gorm:"not null"`
+	Actor     *User        `json:"actor,omitempty" gorm:"foreignKey:ActorID"`
+	Action    string       `json:"action" gorm:"not null"`
+	Changes   FieldChanges `json:"changes" gorm:"type:jsonb;not null;default:'[]'"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// FieldChange is a field's value before and after an event. Values are
+// rendered as strings; empty means unset.
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// FieldChanges is stored as a JSON array
+type FieldChanges []FieldChange
+
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(c)
+	return string(data), err
+}
+
+func (c *FieldChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return errors.New("unsupported type for FieldChanges")
+	}
+}
+
+// auditValues renders the audited fields of a task, in display order
+func (t Task) auditValues() [][2]string {
+	values := [][2]string{
+		{"title", t.Title},
+		{"description", t.Description},
+		{"project_id", formatID(&t.ProjectID)},
+		{"parent_id", formatID(t.ParentID)},
+		{"assignee_id", formatID(t.AssigneeID)},
+		{"status", t.Status},
+		{"priority", t.Priority},
+		{"estimate", t.Estimate},
+		{"recurrence", t.Recurrence},
+		{"due_date", ""},
+	}
+	if t.DueDate != nil {
+		values[len(values)-1][1] = t.DueDate.Format("2006-01-02")
+	}
+	return values
+}
+
+func formatID(id *uint) string {
+	if id == nil || *id == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+// TaskChanges lists the audited fields that differ between two versions of a
+// task. Diff against Task{} to snapshot a created or deleted task.
+func TaskChanges(before, after Task) FieldChanges {
+	from, to := before.auditValues(), after.auditValues()
+
+	var changes FieldChanges
+	for i := range from {
+		if from[i][1] != to[i][1] {
+			changes = append(changes, FieldChange{Field: from[i][0], From: from[i][1], To: to[i][1]})
+		}
+	}
+	return changes
+}
+
+// Workflow is a project's task lifecycle: the statuses a task moves through,
+// which moves are allowed, and the priority and estimate scales. Lists are
+// ordered; sorting by status, priority or estimate follows that order.
+type Workflow struct {
+	// Statuses lists every status; new tasks start in the first one
+	Statuses []string `json:"statuses"`
+	// DoneStatus is the status that counts as finished
+	DoneStatus string `json:"done_status"`
+	// Transitions maps a status to the statuses it may move to. A status
+	// without an entry may move to any status.
+	Transitions map[string][]string `json:"transitions,omitempty"`
+	Priorities  []string            `json:"priorities"`
+	Estimates   []string            `json:"estimates"`
+}
+
+// DefaultWorkflow is the workflow new projects start with
+func DefaultWorkflow() Workflow {
+	return Workflow{
+		Statuses:   []string{"Not Started", "In Progress", "Blocked", "Done"},
+		DoneStatus: "Done",
+		Priorities: []string{"Low", "Medium", "High", "Urgent"},
+		Estimates:  []string{"S", "M", "L", "XL"},
+	}
+}
+
+// Value stores the workflow as JSON; an empty workflow is stored as the default
+func (w Workflow) Value() (driver.Value, error) {
+	if len(w.Statuses) == 0 {
+		w = DefaultWorkflow()
+	}
+	data, err := json.Marshal(w)
+	return string(data), err
+}
+
+// Scan reads a stored workflow; projects without one get the default
+func (w *Workflow) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = DefaultWorkflow()
+		return nil
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	default:
+		return errors.New("unsupported type for Workflow")
+	}
+}
+
+// Validate checks the workflow is self-consistent
+func (w Workflow) Validate() error {
+	if len(w.Statuses) == 0 {
+		return errors.New("workflow needs at least one status")
+	}
+	for name, values := range map[string][]string{"status": w.Statuses, "priority": w.Priorities, "estimate": w.Estimates} {
+		seen := map[string]bool{}
+		for _, value := range values {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("%s names cannot be empty", name)
+			}
+			if seen[value] {
+				return fmt.Errorf("duplicate %s %q", name, value)
+			}
+			seen[value] = true
+		}
+	}
+	if !w.HasStatus(w.DoneStatus) {
+		return fmt.Errorf("done_status %q is not one of the statuses", w.DoneStatus)
+	}
+	for from, targets := range w.Transitions {
+		if !w.HasStatus(from) {
+			return fmt.Errorf("transition from unknown status %q", from)
+		}
+		for _, to := range targets {
+			if !w.HasStatus(to) {
+				return fmt.Errorf("transition from %q to unknown status %q", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+func (w Workflow) InitialStatus() string {
+	return w.Statuses[0]
+}
+
+func (w Workflow) HasStatus(status string) bool {
+	return contains(w.Statuses, status)
+}
+
+func (w Workflow) HasPriority(priority string) bool {
+	return contains(w.Priorities, priority)
+}
+
+func (w Workflow) HasEstimate(estimate string) bool {
+	return contains(w.Estimates, estimate)
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Staying in the same status is always allowed.
+func (w Workflow) CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	targets, restricted := w.Transitions[from]
+	return !restricted || contains(targets, to)
+}
+
+// NextStatuses lists the statuses a task may move to from the given one
+func (w Workflow) NextStatuses(from string) []string {
+	var next []string
+	for _, status := range w.Statuses {
+		if status != from && w.CanTransition(from, status) {
+			next = append(next, status)
+		}
+	}
+	return next
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"task-management-task-service/internal/models"
+	"time"
+)
+
+// Task event types
+const (
+	TaskCreated = "task.created"
+	TaskUpdated = "task.updated"
+	TaskDeleted = "task.deleted"
+)
+
+// publishAttempts bounds how often an event is sent before it is dropped
+const publishAttempts = 3
+
+// subscriber is a service task events are published to, at URL/events
+type subscriber struct {
+	Name   string
+	URL    string
+	Token  string
+	Events []string
+}
+
+var (
+	// subscribers without a URL are skipped
+	subscribers = []subscriber{
+		{
+			Name:   "notification service",
+			URL:    strings.TrimRight(os.Getenv("NOTIFICATION_SERVICE_URL"), "/"),
+			Token:  os.Getenv("NOTIFICATION_SERVICE_TOKEN"),
+			Events: []string{TaskCreated, TaskUpdated},
+		},
+		{
+			// Delivers task events to project webhooks
+			Name:   "project service",
+			URL:    strings.TrimRight(os.Getenv("PROJECT_SERVICE_URL"), "/"),
+			Token:  os.Getenv("PROJECT_SERVICE_TOKEN"),
+			Events: []string{TaskCreated, TaskUpdated, TaskDeleted},
+		},
+	}
+	client = &http.Client{Timeout: 5 * time.Second}
+)
+
+// Event is a task change as subscribers receive it
+type Event struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	ActorID    uint                `json:"actor_id"`
+	Task       Task                `json:"task"`
+	Changes    models.FieldChanges `json:"changes"`
+	OccurredAt time.Time           `json:"occurred_at"`
+}
+
+// Task is the state of a task after the event (before it, for task.deleted)
+type Task struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   uint       `json:"project_id"`
+	AssigneeID  *uint      `json:"assignee_id"`
+	CreatorID   *uint      `json:"creator_id"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	Estimate    string     `json:"estimate"`
+	DueDate     *time.Time `json:"due_date"`
+}
+
+// Publish sends a task event to every subscriber of its type in the
+// background, retrying a few times. Events are dropped, and logged, when a
+// subscriber stays unreachable; subscribers whose URL is unset are skipped.
+func Publish(eventType string, actorID uint, task models.Task, changes models.FieldChanges) {
+	event := Event{
+		ID:      newEventID(),
+		Type:    eventType,
+		ActorID: actorID,
+		Task: Task{
+			ID:          task.ID,
+			Title:       task.Title,
+			Description: task.Description,
+			ProjectID:   task.ProjectID,
+			AssigneeID:  task.AssigneeID,
+			CreatorID:   task.CreatorID,
+			Status:      task.Status,
+			Priority:    task.Priority,
+			Estimate:    task.Estimate,
+			DueDate:     task.DueDate,
+		},
+		Changes:    changes,
+		OccurredAt: time.Now(),
+	}
+
+	for _, sub := range subscribers {
+		if sub.URL == "" || !slices.Contains(sub.Events, eventType) {
+			continue
+		}
+
+		go func() {
+			var err error
+			for attempt := 1; attempt <= publishAttempts; attempt++ {
+				if err = sub.send(event); err == nil {
+					return
+				}
+				time.Sleep(time.Duration(attempt) * time.Second)
+			}
+			log.Printf("Task event publisher: dropped %s event for task %d to the %s: %v", event.Type, event.Task.ID, sub.Name, err)
+		}()
+	}
+}
+
+// send posts one event; subscribers ignore repeats of an ID
+func (sub subscriber) send(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, sub.URL+"/events", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if sub.Token != "" {
+		req.Header.Set("X-Service-Token", sub.Token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s answered %s", sub.Name, resp.Status)
+	}
+	return nil
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
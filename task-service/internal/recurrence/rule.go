@@ -0,0 +1,247 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported frequencies
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// maxSteps bounds the search for the next occurrence, so a rule that can
+// never match again (e.g. BYMONTHDAY=31 every 12 months from April) ends
+const maxSteps = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// Rule is the supported subset of an RFC 5545 RRULE: FREQ, INTERVAL, BYDAY
+// (weekly rules), BYMONTHDAY (monthly rules), COUNT and UNTIL. Occurrences
+// are whole days.
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      *time.Time
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH". A leading
+// "RRULE:" is accepted.
+func Parse(value string) (Rule, error) {
+	rule := Rule{Interval: 1}
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		name, arg, ok := strings.Cut(part, "=")
+		if !ok || arg == "" {
+			return rule, fmt.Errorf("invalid rule part %q", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = strings.ToUpper(arg)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly && rule.Freq != Yearly {
+				return rule, fmt.Errorf("unsupported FREQ %q. Use: DAILY, WEEKLY, MONTHLY, YEARLY", arg)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				return rule, errors.New("INTERVAL must be a positive number")
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(arg), ",") {
+				weekday, ok := weekdays[day]
+				if !ok {
+					return rule, fmt.Errorf("invalid BYDAY value %q", day)
+				}
+				rule.ByDay = append(rule.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			for _, day := range strings.Split(arg, ",") {
+				n, err := strconv.Atoi(day)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return rule, fmt.Errorf("invalid BYMONTHDAY value %q", day)
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, n)
+			}
+		case "COUNT":
+			n, err := strconv.Atoi(arg)
+			if err != nil || n < 1 {
+				return rule, errors.New("COUNT must be a positive number")
+			}
+			rule.Count = n
+		case "UNTIL":
+			// Only the date of a date-time UNTIL (20251231T235959Z) matters
+			if len(arg) > 8 {
+				arg = arg[:8]
+			}
+			until, err := time.Parse("20060102", arg)
+			if err != nil {
+				return rule, errors.New("UNTIL must be a date like 20251231")
+			}
+			rule.Until = &until
+		default:
+			return rule, fmt.Errorf("unsupported rule part %q", name)
+		}
+	}
+
+	if rule.Freq == "" {
+		return rule, errors.New("FREQ is required")
+	}
+	if len(rule.ByDay) > 0 && rule.Freq != Weekly {
+		return rule, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+	if len(rule.ByMonthDay) > 0 && rule.Freq != Monthly {
+		return rule, errors.New("BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	if rule.Count > 0 && rule.Until != nil {
+		return rule, errors.New("COUNT and UNTIL cannot be combined")
+	}
+	return rule, nil
+}
+
+// String renders the rule in canonical RRULE form
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		var days []string
+		for _, weekday := range r.ByDay {
+			days = append(days, strings.ToUpper(weekday.String()[:2]))
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		var days []string
+		for _, day := range r.ByMonthDay {
+			days = append(days, strconv.Itoa(day))
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after the given one, which is
+// occurrence number n of the series (counting from 1). It returns false once
+// the series is over.
+func (r Rule) Next(after time.Time, n int) (time.Time, bool) {
+	if r.Count > 0 && n >= r.Count {
+		return time.Time{}, false
+	}
+
+	after = day(after)
+	var next time.Time
+	var found bool
+	switch r.Freq {
+	case Daily:
+		next, found = after.AddDate(0, 0, r.Interval), true
+	case Weekly:
+		next, found = r.nextWeekly(after)
+	case Monthly:
+		next, found = r.nextMonthly(after)
+	case Yearly:
+		next, found = r.nextYearly(after)
+	}
+
+	if !found || (r.Until != nil && next.After(*r.Until)) {
+		return time.Time{}, false
+	}
+	return next, true
+}
+
+func (r Rule) nextWeekly(after time.Time) (time.Time, bool) {
+	if len(r.ByDay) == 0 {
+		return after.AddDate(0, 0, 7*r.Interval), true
+	}
+
+	// Offsets of the chosen days from Monday, in week order
+	offsets := make([]int, 0, len(r.ByDay))
+	for _, weekday := range r.ByDay {
+		offsets = append(offsets, (int(weekday)+6)%7)
+	}
+	sort.Ints(offsets)
+
+	monday := after.AddDate(0, 0, -((int(after.Weekday()) + 6) % 7))
+	for step := 0; step < maxSteps; step += r.Interval {
+		for _, offset := range offsets {
+			if candidate := monday.AddDate(0, 0, 7*step+offset); candidate.After(after) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r Rule) nextMonthly(after time.Time) (time.Time, bool) {
+	monthDays := r.ByMonthDay
+	if len(monthDays) == 0 {
+		monthDays = []int{after.Day()}
+	}
+
+	first := time.Date(after.Year(), after.Month(), 1, 0, 0, 0, 0, time.UTC)
+	for step := 0; step < maxSteps; step += r.Interval {
+		month := first.AddDate(0, step, 0)
+		length := month.AddDate(0, 1, -1).Day()
+
+		// Resolve negative days from the end of the month and skip days the
+		// month doesn't have, as RFC 5545 does
+		var candidates []int
+		for _, monthDay := range monthDays {
+			if monthDay < 0 {
+				monthDay = length + 1 + monthDay
+			}
+			if monthDay >= 1 && monthDay <= length {
+				candidates = append(candidates, monthDay)
+			}
+		}
+		sort.Ints(candidates)
+
+		for _, monthDay := range candidates {
+			if candidate := month.AddDate(0, 0, monthDay-1); candidate.After(after) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (r Rule) nextYearly(after time.Time) (time.Time, bool) {
+	for step := r.Interval; step < maxSteps; step += r.Interval {
+		candidate := time.Date(after.Year()+step, after.Month(), after.Day(), 0, 0, 0, 0, time.UTC)
+		// February 29th only recurs in leap years
+		if candidate.Day() == after.Day() {
+			return candidate, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// day truncates a time to midnight UTC of its calendar date
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
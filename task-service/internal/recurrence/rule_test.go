@@ -0,0 +1,195 @@
+package recurrence
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// series lists the first occurrences of a rule, starting with start as
+// occurrence 1 and stopping after max or when the series ends
+func series(t *testing.T, value string, start string, max int) []string {
+	t.Helper()
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", value, err)
+	}
+	occurrence, err := time.Parse("2006-01-02", start)
+	if err != nil {
+		t.Fatalf("bad start %q: %v", start, err)
+	}
+
+	dates := []string{start}
+	for n := 1; len(dates) < max; n++ {
+		next, ok := rule.Next(occurrence, n)
+		if !ok {
+			break
+		}
+		dates = append(dates, next.Format("2006-01-02"))
+		occurrence = next
+	}
+	return dates
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start string
+		max   int
+		want  []string
+	}{
+		{
+			name:  "daily every third day",
+			rule:  "FREQ=DAILY;INTERVAL=3",
+			start: "2025-02-26",
+			max:   4,
+			want:  []string{"2025-02-26", "2025-03-01", "2025-03-04", "2025-03-07"},
+		},
+		{
+			name:  "every other week on Monday and Thursday",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			start: "2025-01-06",
+			max:   5,
+			want:  []string{"2025-01-06", "2025-01-09", "2025-01-20", "2025-01-23", "2025-02-03"},
+		},
+		{
+			name:  "every other week starting on a day not in BYDAY",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=TH,MO",
+			start: "2025-01-08",
+			max:   4,
+			want:  []string{"2025-01-08", "2025-01-09", "2025-01-20", "2025-01-23"},
+		},
+		{
+			name:  "every third week, Sunday ends the week",
+			rule:  "FREQ=WEEKLY;INTERVAL=3;BYDAY=SU,MO",
+			start: "2025-01-06",
+			max:   4,
+			want:  []string{"2025-01-06", "2025-01-12", "2025-01-27", "2025-02-02"},
+		},
+		{
+			name:  "the 31st skips shorter months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			start: "2025-01-31",
+			max:   6,
+			want:  []string{"2025-01-31", "2025-03-31", "2025-05-31", "2025-07-31", "2025-08-31", "2025-10-31"},
+		},
+		{
+			name:  "the last day of every month",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start: "2024-01-31",
+			max:   4,
+			want:  []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"},
+		},
+		{
+			name:  "several days a month, every other month",
+			rule:  "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=15,1",
+			start: "2025-01-01",
+			max:   5,
+			want:  []string{"2025-01-01", "2025-01-15", "2025-03-01", "2025-03-15", "2025-05-01"},
+		},
+		{
+			name:  "February 29th only in leap years",
+			rule:  "FREQ=YEARLY",
+			start: "2024-02-29",
+			max:   3,
+			want:  []string{"2024-02-29", "2028-02-29", "2032-02-29"},
+		},
+		{
+			name:  "COUNT includes the first occurrence",
+			rule:  "FREQ=WEEKLY;COUNT=3",
+			start: "2025-01-06",
+			max:   10,
+			want:  []string{"2025-01-06", "2025-01-13", "2025-01-20"},
+		},
+		{
+			name:  "COUNT=1 never repeats",
+			rule:  "FREQ=DAILY;COUNT=1",
+			start: "2025-01-06",
+			max:   10,
+			want:  []string{"2025-01-06"},
+		},
+		{
+			name:  "UNTIL is inclusive",
+			rule:  "FREQ=DAILY;INTERVAL=7;UNTIL=20250115",
+			start: "2025-01-01",
+			max:   10,
+			want:  []string{"2025-01-01", "2025-01-08", "2025-01-15"},
+		},
+		{
+			name:  "UNTIL as a date-time",
+			rule:  "FREQ=MONTHLY;UNTIL=20250331T235959Z",
+			start: "2025-01-10",
+			max:   10,
+			want:  []string{"2025-01-10", "2025-02-10", "2025-03-10"},
+		},
+		{
+			name:  "maxSteps ends a monthly rule that can't match again",
+			rule:  "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=31",
+			start: "2025-04-10",
+			max:   10,
+			want:  []string{"2025-04-10"},
+		},
+		{
+			name:  "maxSteps ends a yearly rule that can't match again",
+			rule:  "FREQ=YEARLY;INTERVAL=1000",
+			start: "2024-02-29",
+			max:   10,
+			want:  []string{"2024-02-29"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := series(t, test.rule, test.start, test.max); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("%s from %s:\n got %v\nwant %v", test.rule, test.start, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNextIgnoresTimeOfDay(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, ok := rule.Next(time.Date(2025, 1, 6, 23, 30, 0, 0, time.UTC), 1)
+	if !ok || !next.Equal(time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Next = %v, %v; want 2025-01-07 00:00 UTC", next, ok)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=mo,th", want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH"},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=31,-1;COUNT=5", want: "FREQ=MONTHLY;BYMONTHDAY=31,-1;COUNT=5"},
+		{value: "FREQ=DAILY;INTERVAL=1;UNTIL=20251231T235959Z", want: "FREQ=DAILY;UNTIL=20251231"},
+		{value: "INTERVAL=2", wantErr: true},
+		{value: "FREQ=HOURLY", wantErr: true},
+		{value: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{value: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{value: "FREQ=WEEKLY;BYMONTHDAY=1", wantErr: true},
+		{value: "FREQ=MONTHLY;BYMONTHDAY=32", wantErr: true},
+		{value: "FREQ=DAILY;COUNT=3;UNTIL=20251231", wantErr: true},
+		{value: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+	}
+	for _, test := range tests {
+		rule, err := Parse(test.value)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %q, want an error", test.value, rule)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", test.value, err)
+			continue
+		}
+		if got := rule.String(); got != test.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
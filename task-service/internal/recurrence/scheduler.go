@@ -0,0 +1,138 @@
+package recurrence
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+	"task-management-task-service/internal/notify"
+	"time"
+)
+
+// Materialize creates the next occurrence of a recurring task once the task
+// is finished or its due date has arrived. Occurrences whose dates have
+// already passed are skipped, so an overdue series resumes from today, and
+// the last task of a finished series stops recurring. It does nothing for
+// tasks that aren't recurring or whose next occurrence exists. The
+// occurrence is attributed to actorID, or to the series creator when actorID
+// is 0.
+func Materialize(tx *gorm.DB, taskID uint, actorID uint, now time.Time) (*models.Task, error) {
+	// Lock the task so concurrent schedulers and updates create one occurrence
+	var task models.Task
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&task, taskID).Error; err != nil {
+		return nil, err
+	}
+	if task.Recurrence == "" || task.NextOccurrenceID != nil || task.DueDate == nil {
+		return nil, nil
+	}
+
+	// Lock the project, as task writes do, so its workflow can't change
+	// before the occurrence is created
+	var project models.Project
+	if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).First(&project, task.ProjectID).Error; err != nil {
+		return nil, err
+	}
+
+	today := day(now)
+	if task.Status != project.Workflow.DoneStatus && task.DueDate.After(today) {
+		return nil, nil
+	}
+
+	rule, err := Parse(task.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrence := task.Occurrence
+	dueDate, ok := rule.Next(*task.DueDate, occurrence)
+	occurrence++
+	for ok && dueDate.Before(today) {
+		dueDate, ok = rule.Next(dueDate, occurrence)
+		occurrence++
+	}
+	if !ok {
+		// The series is over; the last task no longer recurs
+		return nil, tx.Model(&task).UpdateColumn("recurrence", "").Error
+	}
+
+	next := models.Task{
+		Title:       task.Title,
+		Description: task.Description,
+		ProjectID:   task.ProjectID,
+		AssigneeID:  task.AssigneeID,
+		CreatorID:   task.CreatorID,
+		Status:      project.Workflow.InitialStatus(),
+		Priority:    task.Priority,
+		Estimate:    task.Estimate,
+		DueDate:     &dueDate,
+		Recurrence:  task.Recurrence,
+		Occurrence:  occurrence,
+	}
+	if err := tx.Create(&next).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&task).UpdateColumn("next_occurrence_id", next.ID).Error; err != nil {
+		return nil, err
+	}
+
+	if actorID == 0 {
+		actorID = *task.CreatorID
+	}
+	err = tx.Create(&models.TaskEvent{
+		TaskID:    next.ID,
+		ProjectID: next.ProjectID,
+		ActorID:   actorID,
+		Action:    models.TaskCreated,
+		Changes:   models.TaskChanges(models.Task{}, next),
+	}).Error
+	return &next, err
+}
+
+// Start runs the scheduler in the background, checking recurring tasks every
+// interval
+func Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			run(time.Now())
+			<-ticker.C
+		}
+	}()
+}
+
+// run materializes the next occurrence of every recurring task that is
+// finished or due
+func run(now time.Time) {
+	var taskIDs []uint
+	err := database.DB.Model(&models.Task{}).
+		Where("recurrence <> '' AND next_occurrence_id IS NULL AND due_date IS NOT NULL").
+		Where("due_date <= ? OR tasks.status = (SELECT projects.workflow->>'done_status' FROM projects WHERE projects.id = tasks.project_id)", day(now)).
+		Pluck("id", &taskIDs).Error
+	if err != nil {
+		log.Printf("Recurrence scheduler: failed to find due tasks: %v", err)
+		return
+	}
+
+	for _, taskID := range taskIDs {
+		var next *models.Task
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			var err error
+			next, err = Materialize(tx, taskID, 0, now)
+			if err == nil && next != nil {
+				log.Printf("Recurrence scheduler: task %d recurs as task %d due %s", taskID, next.ID, next.DueDate.Format("2006-01-02"))
+			}
+			return err
+		})
+		if err != nil {
+			log.Printf("Recurrence scheduler: failed to materialize task %d: %v", taskID, err)
+			continue
+		}
+		if next != nil {
+			// No one acted, so the creator and assignee both hear about it
+			notify.Publish(notify.TaskCreated, 0, *next, nil)
+		}
+	}
+}
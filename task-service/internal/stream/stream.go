@@ -0,0 +1,200 @@
+package stream
+
+import (
+	"gorm.io/gorm"
+	"log"
+	"sync"
+	"task-management-task-service/internal/database"
+	"task-management-task-service/internal/models"
+	"time"
+)
+
+const (
+	// bufferSize is how many events a subscriber may fall behind by before
+	// it is dropped; its client then reconnects and replays what it missed
+	bufferSize = 256
+	// lookback re-reads recent events on every poll, so an event whose
+	// transaction committed after a later-numbered one is still picked up
+	lookback = 5 * time.Second
+)
+
+// Event is a task change as pushed to streaming clients. Its ID is the
+// task_events ID, which clients send back as Last-Event-ID.
+type Event struct {
+	ID        uint                `json:"id"`
+	Type      string              `json:"type"`
+	TaskID    uint                `json:"task_id"`
+	ProjectID uint                `json:"project_id"`
+	ActorID   uint                `json:"actor_id"`
+	Changes   models.FieldChanges `json:"changes"`
+	Task      *Task               `json:"task"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+// Task is the current state of the changed task, or nil once it is deleted
+// or has moved out of the project the event was recorded in
+type Task struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	ProjectID   uint       `json:"project_id"`
+	ParentID    *uint      `json:"parent_id"`
+	AssigneeID  *uint      `json:"assignee_id"`
+	CreatorID   *uint      `json:"creator_id"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	Estimate    string     `json:"estimate"`
+	DueDate     *time.Time `json:"due_date"`
+	Recurrence  string     `json:"recurrence"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Subscription receives every task event published after it was made.
+// Events is closed if the subscriber falls too far behind.
+type Subscription struct {
+	Events chan Event
+}
+
+var (
+	mu          sync.Mutex
+	subscribers = map[*Subscription]bool{}
+)
+
+// Subscribe starts receiving live events
+func Subscribe() *Subscription {
+	sub := &Subscription{Events: make(chan Event, bufferSize)}
+	mu.Lock()
+	subscribers[sub] = true
+	mu.Unlock()
+	return sub
+}
+
+// Close stops receiving events
+func (s *Subscription) Close() {
+	mu.Lock()
+	defer mu.Unlock()
+	if subscribers[s] {
+		delete(subscribers, s)
+		close(s.Events)
+	}
+}
+
+func broadcast(event Event) {
+	mu.Lock()
+	defer mu.Unlock()
+	for sub := range subscribers {
+		select {
+		case sub.Events <- event:
+		default:
+			log.Printf("Task stream: dropping a subscriber that fell %d events behind", bufferSize)
+			delete(subscribers, sub)
+			close(sub.Events)
+		}
+	}
+}
+
+// Start tails the task audit trail every interval and pushes new events to
+// subscribers. The audit trail is shared by every task-service instance, so
+// subscribers see changes made through any of them.
+func Start(interval time.Duration) {
+	var lastID uint
+	if err := database.DB.Model(&models.TaskEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&lastID).Error; err != nil {
+		log.Printf("Task stream: failed to find the latest event: %v", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		// IDs sent within the lookback window, so re-read events aren't resent
+		sent := map[uint]time.Time{}
+		for range ticker.C {
+			now := time.Now()
+			events, err := Load(database.DB.
+				Where("task_events.id > ? OR task_events.created_at > ?", lastID, now.Add(-lookback)), 0)
+			if err != nil {
+				log.Printf("Task stream: failed to load events: %v", err)
+				continue
+			}
+
+			for _, event := range events {
+				if _, ok := sent[event.ID]; ok {
+					continue
+				}
+				sent[event.ID] = event.CreatedAt
+				lastID = max(lastID, event.ID)
+				broadcast(event)
+			}
+			for id, createdAt := range sent {
+				if createdAt.Before(now.Add(-2 * lookback)) {
+					delete(sent, id)
+				}
+			}
+		}
+	}()
+}
+
+// Load reads task events matching the given scope, oldest first, with the
+// current state of their tasks. Events belong to the project the task was in
+// when they were recorded. limit 0 means no limit.
+func Load(scope *gorm.DB, limit int) ([]Event, error) {
+	var rows []models.TaskEvent
+	query := scope.Model(&models.TaskEvent{}).Order("task_events.id")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	taskIDs := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		taskIDs = append(taskIDs, row.TaskID)
+	}
+	var tasks []models.Task
+	if err := database.DB.Where("id IN ?", taskIDs).Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	current := make(map[uint]*Task, len(tasks))
+	for _, task := range tasks {
+		current[task.ID] = &Task{
+			ID:          task.ID,
+			Title:       task.Title,
+			Description: task.Description,
+			ProjectID:   task.ProjectID,
+			ParentID:    task.ParentID,
+			AssigneeID:  task.AssigneeID,
+			CreatorID:   task.CreatorID,
+			Status:      task.Status,
+			Priority:    task.Priority,
+			Estimate:    task.Estimate,
+			DueDate:     task.DueDate,
+			Recurrence:  task.Recurrence,
+			UpdatedAt:   task.UpdatedAt,
+		}
+	}
+
+	events := make([]Event, 0, len(rows))
+	for _, row := range rows {
+		// A task that moved since is only shown to its new project's events,
+		// whose subscribers may see it
+		task := current[row.TaskID]
+		if task != nil && task.ProjectID != row.ProjectID {
+			task = nil
+		}
+		events = append(events, Event{
+			ID:        row.ID,
+			Type:      "task." + row.Action,
+			TaskID:    row.TaskID,
+			ProjectID: row.ProjectID,
+			ActorID:   row.ActorID,
+			Changes:   row.Changes,
+			Task:      task,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return events, nil
+}
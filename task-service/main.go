@@ -3,9 +3,13 @@ package main
 import (
 	"fmt"
 	"log"
+	"os"
 	"task-management-task-service/internal/database"
 	"task-management-task-service/internal/handlers"
 	"task-management-task-service/internal/middleware"
+	"task-management-task-service/internal/recurrence"
+	"task-management-task-service/internal/stream"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,6 +18,12 @@ func main() {
 	// Connect to database
 	database.Connect()
 
+	// Create the next occurrences of recurring tasks in the background
+	recurrence.Start(recurrenceInterval())
+
+	// Push task changes to streaming clients as they are recorded
+	stream.Start(streamInterval())
+
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
@@ -42,21 +52,79 @@ func main() {
 	{
 		tasks.POST("", handlers.CreateTask)
 		tasks.GET("", handlers.GetTasks)
+		tasks.GET("/search", handlers.SearchTasks)
+		tasks.GET("/stream", handlers.StreamTasks)
 		tasks.GET("/:id", handlers.GetTaskByID)
 		tasks.PUT("/:id", handlers.UpdateTask)
+		tasks.PATCH("/:id/assign", handlers.AssignTask)
+		tasks.GET("/:id/history", handlers.GetTaskHistory)
 		tasks.DELETE("/:id", handlers.DeleteTask)
+
+		tasks.POST("/:id/comments", handlers.CreateComment)
+		tasks.GET("/:id/comments", handlers.GetComments)
+		tasks.PUT("/:id/comments/:comment_id", handlers.UpdateComment)
+		tasks.DELETE("/:id/comments/:comment_id", handlers.DeleteComment)
+
+		tasks.PATCH("/:id/parent", handlers.SetTaskParent)
+		tasks.GET("/:id/subtasks", handlers.GetSubtasks)
+		tasks.GET("/:id/dependencies", handlers.GetTaskDependencies)
+		tasks.POST("/:id/dependencies", handlers.AddTaskDependency)
+		tasks.DELETE("/:id/dependencies/:other_id", handlers.RemoveTaskDependency)
+	}
+
+	// Notification routes (all protected)
+	notifications := r.Group("/notifications")
+	notifications.Use(middleware.RequireAuth())
+	{
+		notifications.GET("", handlers.GetNotifications)
+		notifications.PATCH("/read", handlers.MarkAllNotificationsRead)
+		notifications.PATCH("/:id/read", handlers.MarkNotificationRead)
 	}
 
 	log.Println("📋 Task Service starting on port 8084")
 	log.Println("📋 Available endpoints:")
 	log.Println("   GET  /health")
 	log.Println("   POST /tasks")
-	log.Println("   GET  /tasks (with filtering: ?project_id=X&status=Y&priority=Z)")
+	log.Println("   GET  /tasks (with filtering: ?project_id=X&status=Y&priority=Z&assignee_id=A&creator_id=C&parent_id=P)")
+	log.Println("   GET  /tasks/search?q=")
+	log.Println("   GET  /tasks/stream (server-sent events, optional ?project_id=X)")
 	log.Println("   GET  /tasks/:id")
 	log.Println("   PUT  /tasks/:id")
+	log.Println("   PATCH /tasks/:id/assign")
+	log.Println("   GET  /tasks/:id/history (optional ?field=status)")
 	log.Println("   DELETE /tasks/:id")
+	log.Println("   POST /tasks/:id/comments")
+	log.Println("   GET  /tasks/:id/comments")
+	log.Println("   PUT  /tasks/:id/comments/:comment_id")
+	log.Println("   DELETE /tasks/:id/comments/:comment_id")
+	log.Println("   PATCH /tasks/:id/parent")
+	log.Println("   GET  /tasks/:id/subtasks")
+	log.Println("   GET  /tasks/:id/dependencies")
+	log.Println("   POST /tasks/:id/dependencies")
+	log.Println("   DELETE /tasks/:id/dependencies/:other_id")
+	log.Println("   GET  /notifications (optional ?unread=true)")
+	log.Println("   PATCH /notifications/read")
+	log.Println("   PATCH /notifications/:id/read")
 
 	if err := r.Run(":8084"); err != nil {
 		log.Fatal("Failed to start task service:", err)
 	}
 }
+
+// recurrenceInterval reads how often recurring tasks are checked from
+// RECURRENCE_INTERVAL (e.g. 30s, 5m), defaulting to every minute
+func recurrenceInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("RECURRENCE_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return time.Minute
+}
+
+// streamInterval reads how often new task changes are picked up for
+// streaming clients from STREAM_INTERVAL, defaulting to every second
+func streamInterval() time.Duration {
+	if interval, err := time.ParseDuration(os.Getenv("STREAM_INTERVAL")); err == nil && interval > 0 {
+		return interval
+	}
+	return time.Second
+}
@@ -1,25 +0,0 @@
-package utils
-
-import (
-	"errors"
-	"github.com/golang-jwt/jwt/v5"
-	"os"
-)
-
-var jwtSecret = []byte(os.Getenv("JWT_SECRET"))
-
-func ValidateJWT(tokenString string) (jwt.MapClaims, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		return claims, nil
-	}
-
-	return nil, errors.New("invalid token")
-}